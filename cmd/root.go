@@ -53,10 +53,10 @@ func setErrorHandlers(conn *nats.Conn) {
 	}
 
 	conn.SetErrorHandler(func(c *nats.Conn, s *nats.Subscription, err error) {
-		slog.Error("NATS error", err)
+		slog.Error("NATS error", "err", err)
 	})
 	conn.SetDisconnectHandler(func(c *nats.Conn) {
-		slog.Error("NATS disconnected", c.LastError())
+		slog.Error("NATS disconnected", "err", c.LastError())
 	})
 }
 
@@ -7,11 +7,14 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/synternet/data-layer-sdk/pkg/service"
 	"github.com/synternet/osmosis-publisher/internal/osmosis"
 	"github.com/synternet/osmosis-publisher/pkg/dtlWithSocket"
+	"github.com/synternet/osmosis-publisher/pkg/payload"
 )
 
 var (
@@ -19,10 +22,44 @@ var (
 	flagTendermintAPI *string
 	flagRPCAPI        *string
 	flagGRPCAPI       *string
+	flagGRPCArchive   *string
+	flagArchiveDepth  *uint64
+	flagConcurrency   *int
+	flagRateLimit     *float64
+	flagGRPCTLS       *bool
+	flagGRPCTLSCA     *string
+	flagGRPCTLSCert   *string
+	flagGRPCTLSKey    *string
+	flagGRPCTLSServer *string
+	flagGRPCHeaders   *string
+	flagTMHeaders     *string
+	flagTMUser        *string
+	flagTMPassword    *string
+	flagEventQueries  *string
+	flagBlockDeadline *time.Duration
+	flagMaxCatchup    *uint64
+	flagSpillDir      *string
+	flagSpillMaxBytes *int64
+	flagOutboxDir     *string
+	flagOutboxBytes   *int64
+	flagOutboxAge     *time.Duration
+	flagJetStream     *bool
+	flagEncoding      *string
+	flagCompression   *string
+	flagMaxPayload    *int64
+	flagStreams       = make(map[string]*bool)
+	flagTxSubjects    *string
+	flagPoolSubjects  *bool
+	flagQuery         *bool
 	flagPricesSubject *string
 	flagBlocks        *uint64
 	flagSocketAddr    *string
+	flagSocketListen  *bool
+	flagSocketFraming *string
+	flagSinks         *string
+	flagSinkConfig    *string
 	metricsUrl        *string
+	flagAPIAddr       *string
 )
 
 // startCmd represents the nft command
@@ -42,8 +79,54 @@ var startCmd = &cobra.Command{
 			}
 			poolIds[i] = uint64(id)
 		}
+
+		grpcHeaders, err := ParseHeaders(*flagGRPCHeaders)
+		if err != nil {
+			panic(fmt.Errorf("Bad gRPC headers: %w", err))
+		}
+		tendermintHeaders, err := ParseHeaders(*flagTMHeaders)
+		if err != nil {
+			panic(fmt.Errorf("Bad Tendermint headers: %w", err))
+		}
+
+		var grpcTLS *osmosis.TLSConfig
+		if *flagGRPCTLS || *flagGRPCTLSCA != "" || *flagGRPCTLSCert != "" || *flagGRPCTLSKey != "" || *flagGRPCTLSServer != "" {
+			grpcTLS = &osmosis.TLSConfig{
+				CAFile:     *flagGRPCTLSCA,
+				CertFile:   *flagGRPCTLSCert,
+				KeyFile:    *flagGRPCTLSKey,
+				ServerName: *flagGRPCTLSServer,
+			}
+		}
+
+		var sinks []dtlWithSocket.SinkConfig
+		if *flagSinkConfig != "" {
+			sinks, err = dtlWithSocket.LoadSinkConfig(*flagSinkConfig)
+			if err != nil {
+				panic(err)
+			}
+		}
+		for _, spec := range SplitAndTrimEmpty(*flagSinks, ",", " \t\r\n\b") {
+			sink, err := dtlWithSocket.ParseSink(spec)
+			if err != nil {
+				panic(err)
+			}
+			sinks = append(sinks, sink)
+		}
+
+		codec, err := osmosis.NewCodec(*flagEncoding)
+		if err != nil {
+			panic(err)
+		}
+
+		sinkCodec, err := osmosis.NewCodec(osmosis.EncodingProtobuf)
+		if err != nil {
+			panic(err)
+		}
+
 		publisher, err := osmosis.New(
 			database,
+			service.WithCodec(codec),
 			service.WithContext(ctx),
 			service.WithTelemetryPeriod(*flagTelemetryPeriod),
 			service.WithName(*flagPublisherName),
@@ -55,22 +138,53 @@ var startCmd = &cobra.Command{
 			service.WithPemPrivateKey(*flagPemFile),
 			service.WithVerbose(*flagVerbose),
 			dtlWithSocket.WithPubSocket(*flagSocketAddr),
-			osmosis.WithTendermintAPI(*flagTendermintAPI),
+			dtlWithSocket.WithSocketSubjects(osmosis.TxSocketSubjects(*flagTxSubjects)...),
+			dtlWithSocket.WithSocketListen(*flagSocketListen),
+			dtlWithSocket.WithSocketFraming(*flagSocketFraming),
+			dtlWithSocket.WithSinkCodec(sinkCodec),
+			dtlWithSocket.WithSinks(sinks...),
+			dtlWithSocket.WithOutbox(*flagOutboxDir, *flagOutboxBytes, *flagOutboxAge),
+			dtlWithSocket.WithJetStream(*flagJetStream),
+			dtlWithSocket.WithPayload(*flagCompression, *flagMaxPayload),
+			osmosis.WithTendermintAPI(SplitAndTrimEmpty(*flagTendermintAPI, ",", " \t\r\n\b")),
 			osmosis.WithRPCAPI(*flagRPCAPI),
-			osmosis.WithGRPCAPI(*flagGRPCAPI),
+			osmosis.WithGRPCAPI(SplitAndTrimEmpty(*flagGRPCAPI, ",", " \t\r\n\b")),
+			osmosis.WithGRPCArchiveAPI(SplitAndTrimEmpty(*flagGRPCArchive, ",", " \t\r\n\b")),
+			osmosis.WithArchiveDepth(*flagArchiveDepth),
+			osmosis.WithGRPCTLS(grpcTLS),
+			osmosis.WithGRPCHeaders(grpcHeaders),
+			osmosis.WithTendermintHeaders(tendermintHeaders),
+			osmosis.WithTendermintBasicAuth(*flagTMUser, *flagTMPassword),
+			osmosis.WithEventQueries(SplitAndTrimEmpty(*flagEventQueries, ",", " \t\r\n\b")),
+			osmosis.WithBlockDeadline(*flagBlockDeadline),
+			osmosis.WithSpillDir(*flagSpillDir),
+			osmosis.WithSpillMaxBytes(*flagSpillMaxBytes),
+			osmosis.WithMaxCatchupBlocks(*flagMaxCatchup),
+			osmosis.WithRPCConcurrency(*flagConcurrency),
+			osmosis.WithRPCRateLimit(*flagRateLimit),
 			osmosis.WithPoolIds(poolIds),
 			osmosis.WithBlocksToIndex(*flagBlocks),
 			osmosis.WithPriceSubject(*flagPricesSubject),
 			osmosis.WithMetrics(*metricsUrl),
+			osmosis.WithAPI(*flagAPIAddr),
 			osmosis.WithSocketAddr(*flagSocketAddr),
+			osmosis.WithStream(osmosis.StreamBlock, *flagStreams[osmosis.StreamBlock]),
+			osmosis.WithStream(osmosis.StreamTx, *flagStreams[osmosis.StreamTx]),
+			osmosis.WithStream(osmosis.StreamMempool, *flagStreams[osmosis.StreamMempool]),
+			osmosis.WithStream(osmosis.StreamStatePools, *flagStreams[osmosis.StreamStatePools]),
+			osmosis.WithStream(osmosis.StreamVolumePool, *flagStreams[osmosis.StreamVolumePool]),
+			osmosis.WithStream(osmosis.StreamSwaps, *flagStreams[osmosis.StreamSwaps]),
+			osmosis.WithTxSubjects(*flagTxSubjects),
+			osmosis.WithPoolSubjects(*flagPoolSubjects),
+			osmosis.WithQuery(*flagQuery),
 		)
-		if publisher == nil {
-			return
-		}
 		if err != nil {
 			slog.Error("publisher failed", "err", err)
 			return
 		}
+		if publisher == nil {
+			return
+		}
 
 		pubCtx := publisher.Start()
 		defer publisher.Close()
@@ -90,14 +204,47 @@ func init() {
 
 	const (
 		METRICS_URL        = "PROMETHEUS_EXPORT"
+		API_ADDR           = "API_ADDR"
 		OSMOSIS_TENDERMINT = "TENDERMINT_API"
 		OSMOSIS_RPC        = "APP_API"
 		OSMOSIS_GRPC       = "GRPC_API"
+		OSMOSIS_ARCHIVE    = "GRPC_ARCHIVE_API"
+		ARCHIVE_DEPTH      = "ARCHIVE_DEPTH"
+		GRPC_TLS           = "GRPC_TLS"
+		GRPC_TLS_CA        = "GRPC_TLS_CA"
+		GRPC_TLS_CERT      = "GRPC_TLS_CERT"
+		GRPC_TLS_KEY       = "GRPC_TLS_KEY"
+		GRPC_TLS_SERVER    = "GRPC_TLS_SERVER_NAME"
+		GRPC_HEADERS       = "GRPC_HEADERS"
+		TENDERMINT_HEADERS = "TENDERMINT_HEADERS"
+		TENDERMINT_USER    = "TENDERMINT_USER"
+		TENDERMINT_PASS    = "TENDERMINT_PASSWORD"
+		EVENT_QUERIES      = "EVENT_QUERIES"
+		BLOCK_DEADLINE     = "BLOCK_DEADLINE"
+		MAX_CATCHUP_BLOCKS = "MAX_CATCHUP_BLOCKS"
+		SPILL_DIR          = "SPILL_DIR"
+		SPILL_MAX_BYTES    = "SPILL_MAX_BYTES"
+		OUTBOX_DIR         = "OUTBOX_DIR"
+		OUTBOX_MAX_BYTES   = "OUTBOX_MAX_BYTES"
+		OUTBOX_MAX_AGE     = "OUTBOX_MAX_AGE"
+		JETSTREAM          = "JETSTREAM"
+		ENCODING           = "ENCODING"
+		COMPRESSION        = "COMPRESSION"
+		MAX_PAYLOAD        = "MAX_PAYLOAD"
+		TX_SUBJECTS        = "TX_SUBJECTS"
+		POOL_SUBJECTS      = "POOL_SUBJECTS"
+		QUERY              = "QUERY"
+		RPC_CONCURRENCY    = "RPC_CONCURRENCY"
+		RPC_RATE_LIMIT     = "RPC_RATE_LIMIT"
 		OSMOSIS_NAME       = "PUBLISHER_NAME"
 		OSMOSIS_POOLS      = "POOL_IDS"
 		OSMOSIS_BLOCKS     = "BLOCKS_TO_INDEX"
 		PRICES_SUBJECT     = "PRICES_SUBJECT"
 		SOCKET_ADDR        = "SOCKET_ADDR"
+		SOCKET_LISTEN      = "SOCKET_LISTEN"
+		SOCKET_FRAMING     = "SOCKET_FRAMING"
+		SINKS              = "SINKS"
+		SINK_CONFIG        = "SINK_CONFIG"
 	)
 
 	setDefault(OSMOSIS_TENDERMINT, "tcp://localhost:26657")
@@ -106,18 +253,143 @@ func init() {
 	setDefault(OSMOSIS_NAME, "osmosis")
 	setDefault(OSMOSIS_POOLS, "1,1077,1223,678,1251,1265,1133,1220,1247,1135,1221,1248")
 	setDefault(OSMOSIS_BLOCKS, "20000")
+	setDefault(BLOCK_DEADLINE, "10s")
+	setDefault(MAX_CATCHUP_BLOCKS, "720")
+	setDefault(SPILL_MAX_BYTES, "268435456")
+	setDefault(OUTBOX_MAX_BYTES, "1073741824")
+	setDefault(OUTBOX_MAX_AGE, "24h")
+	setDefault(RPC_CONCURRENCY, "8")
+	setDefault(RPC_RATE_LIMIT, "100")
+	setDefault(ENCODING, osmosis.EncodingJSON)
+	setDefault(TX_SUBJECTS, osmosis.TxSubjectsSingle)
 	setDefault(PRICES_SUBJECT, "syntropy_defi.price.single.OSMO")
 
 	metricsUrl = startCmd.Flags().String("prometheus-export", os.Getenv(METRICS_URL), "Interface address and port for Prometheus export (e.g. 0.0.0.0:2112)")
+	flagAPIAddr = startCmd.Flags().String("api", os.Getenv(API_ADDR), "Interface address and port for the HTTP API over the database (e.g. 0.0.0.0:8080)")
 
 	flagPublisherName = startCmd.Flags().String("publisher-name", os.Getenv(OSMOSIS_NAME), "NATS publisher name as in {prefix}.{name}.>")
-	flagTendermintAPI = startCmd.Flags().String("tendermint-api", os.Getenv(OSMOSIS_TENDERMINT), "Full address to the Tendermint RPC")
+	flagTendermintAPI = startCmd.Flags().String("tendermint-api", os.Getenv(OSMOSIS_TENDERMINT), "Full addresses to the Tendermint RPC (separated by comma)")
 	flagRPCAPI = startCmd.Flags().String("app-api", os.Getenv(OSMOSIS_RPC), "Full address to the Applications RPC")
-	flagGRPCAPI = startCmd.Flags().String("grpc-api", os.Getenv(OSMOSIS_GRPC), "Full address to the Applications gRPC")
+	flagGRPCAPI = startCmd.Flags().String("grpc-api", os.Getenv(OSMOSIS_GRPC), "Full addresses to the Applications gRPC (separated by comma)")
+
+	flagGRPCArchive = startCmd.Flags().String("grpc-archive-api", os.Getenv(OSMOSIS_ARCHIVE), "Full addresses to the archive node gRPC used for historical state queries (separated by comma)")
+
+	grpcTLS, _ := strconv.ParseBool(os.Getenv(GRPC_TLS))
+	flagGRPCTLS = startCmd.Flags().Bool("grpc-tls", grpcTLS, "Use TLS for gRPC connections (implied by any of the other --grpc-tls-* flags)")
+	flagGRPCTLSCA = startCmd.Flags().String("grpc-tls-ca", os.Getenv(GRPC_TLS_CA), "CA bundle to verify gRPC server certificates (system pool by default)")
+	flagGRPCTLSCert = startCmd.Flags().String("grpc-tls-cert", os.Getenv(GRPC_TLS_CERT), "Client certificate for gRPC connections")
+	flagGRPCTLSKey = startCmd.Flags().String("grpc-tls-key", os.Getenv(GRPC_TLS_KEY), "Client certificate key for gRPC connections")
+	flagGRPCTLSServer = startCmd.Flags().String("grpc-tls-server-name", os.Getenv(GRPC_TLS_SERVER), "Override the server name used to verify gRPC server certificates")
+	flagGRPCHeaders = startCmd.Flags().String("grpc-headers", os.Getenv(GRPC_HEADERS), "Metadata sent with every gRPC call (key=value separated by comma)")
+	flagTMHeaders = startCmd.Flags().String("tendermint-headers", os.Getenv(TENDERMINT_HEADERS), "HTTP headers sent with every Tendermint RPC request (key=value separated by comma)")
+	flagTMUser = startCmd.Flags().String("tendermint-user", os.Getenv(TENDERMINT_USER), "Basic auth user for Tendermint RPC")
+	flagTMPassword = startCmd.Flags().String("tendermint-password", os.Getenv(TENDERMINT_PASS), "Basic auth password for Tendermint RPC")
+
+	envArchiveDepth := os.Getenv(ARCHIVE_DEPTH)
+	archiveDepth, err := strconv.ParseUint(envArchiveDepth, 10, 64)
+	if err != nil && envArchiveDepth != "" {
+		slog.Warn("Bad archive depth format", "err", err, "default", archiveDepth)
+	}
+	flagArchiveDepth = startCmd.Flags().Uint64("archive-depth", archiveDepth, "Route state queries older than this number of blocks to the archive node (0 - only pruned heights)")
+
+	concurrency, err := strconv.ParseInt(os.Getenv(RPC_CONCURRENCY), 10, 64)
+	if err != nil {
+		concurrency = 8
+		slog.Warn("Bad RPC concurrency format", "err", err, "default", concurrency)
+	}
+	flagConcurrency = startCmd.Flags().Int("rpc-concurrency", int(concurrency), "Maximum number of concurrent pool queries to the node")
+
+	rateLimit, err := strconv.ParseFloat(os.Getenv(RPC_RATE_LIMIT), 64)
+	if err != nil {
+		rateLimit = 100
+		slog.Warn("Bad RPC rate limit format", "err", err, "default", rateLimit)
+	}
+	flagRateLimit = startCmd.Flags().Float64("rpc-rate-limit", rateLimit, "Maximum number of state queries per second to the node (0 - unlimited)")
+
+	flagEventQueries = startCmd.Flags().String("event-queries", os.Getenv(EVENT_QUERIES), fmt.Sprintf("Tendermint queries of transactions with pool events (separated by comma, default %q)", strings.Join(osmosis.DefaultEventQueries, ",")))
+
+	blockDeadline, err := time.ParseDuration(os.Getenv(BLOCK_DEADLINE))
+	if err != nil {
+		blockDeadline = time.Second * 10
+		slog.Warn("Bad block deadline format", "err", err, "default", blockDeadline)
+	}
+	flagBlockDeadline = startCmd.Flags().Duration("block-deadline", blockDeadline, "Skip pools of interest computation of a block that is not done within this time (0 - no deadline)")
+
+	maxCatchup, err := strconv.ParseUint(os.Getenv(MAX_CATCHUP_BLOCKS), 10, 64)
+	if err != nil {
+		maxCatchup = 720
+		slog.Warn("Bad max catchup blocks format", "err", err, "default", maxCatchup)
+	}
+	flagMaxCatchup = startCmd.Flags().Uint64("max-catchup-blocks", maxCatchup, "Maximum number of missed blocks published after a restart or a connection loss")
+
+	flagSpillDir = startCmd.Flags().String("spill-dir", os.Getenv(SPILL_DIR), "Directory for events that do not fit into memory queues (system temporary directory by default)")
+
+	spillMaxBytes, err := strconv.ParseInt(os.Getenv(SPILL_MAX_BYTES), 10, 64)
+	if err != nil {
+		spillMaxBytes = 256 << 20
+		slog.Warn("Bad spill max bytes format", "err", err, "default", spillMaxBytes)
+	}
+	flagSpillMaxBytes = startCmd.Flags().Int64("spill-max-bytes", spillMaxBytes, "Disk budget in bytes of each subscription's spill file (0 - drop events when memory queue is full)")
+
+	flagOutboxDir = startCmd.Flags().String("outbox-dir", os.Getenv(OUTBOX_DIR), "Directory of the outbox that keeps messages while NATS is unavailable (empty - disabled)")
+
+	outboxBytes, err := strconv.ParseInt(os.Getenv(OUTBOX_MAX_BYTES), 10, 64)
+	if err != nil {
+		outboxBytes = 1 << 30
+		slog.Warn("Bad outbox max bytes format", "err", err, "default", outboxBytes)
+	}
+	flagOutboxBytes = startCmd.Flags().Int64("outbox-max-bytes", outboxBytes, "Maximum size of the outbox in bytes (0 - unlimited)")
+
+	outboxAge, err := time.ParseDuration(os.Getenv(OUTBOX_MAX_AGE))
+	if err != nil {
+		outboxAge = time.Hour * 24
+		slog.Warn("Bad outbox max age format", "err", err, "default", outboxAge)
+	}
+	flagOutboxAge = startCmd.Flags().Duration("outbox-max-age", outboxAge, "Messages older than this are not replayed from the outbox (0 - no limit)")
+
+	jetStream, _ := strconv.ParseBool(os.Getenv(JETSTREAM))
+	flagJetStream = startCmd.Flags().Bool("jetstream", jetStream, "Publish with JetStream and deterministic message ids, so that streams drop duplicates")
+
+	flagEncoding = startCmd.Flags().String("encoding", os.Getenv(ENCODING), fmt.Sprintf("Encoding of published messages (%s or %s)", osmosis.EncodingJSON, osmosis.EncodingProtobuf))
+
+	flagCompression = startCmd.Flags().String("compression", os.Getenv(COMPRESSION), fmt.Sprintf("Compression of published messages (%s or %s, empty - none)", payload.EncodingZstd, payload.EncodingGzip))
+
+	envMaxPayload := os.Getenv(MAX_PAYLOAD)
+	maxPayload, err := strconv.ParseInt(envMaxPayload, 10, 64)
+	if err != nil && envMaxPayload != "" {
+		slog.Warn("Bad max payload format", "err", err, "default", maxPayload)
+	}
+	flagMaxPayload = startCmd.Flags().Int64("max-payload", maxPayload, "Messages larger than this are split into chunks (0 - max payload of the NATS server)")
+
+	for _, stream := range osmosis.Streams {
+		// e.g. STREAM_STATE_POOLS and --stream-state-pools for state.pools
+		env := "STREAM_" + strings.ToUpper(strings.ReplaceAll(stream, ".", "_"))
+		enabled, err := strconv.ParseBool(os.Getenv(env))
+		if err != nil {
+			enabled = true
+			if os.Getenv(env) != "" {
+				slog.Warn("Bad stream flag format", "env", env, "err", err, "default", enabled)
+			}
+		}
+		flagStreams[stream] = startCmd.Flags().Bool("stream-"+strings.ReplaceAll(stream, ".", "-"), enabled, fmt.Sprintf("Publish %s messages", stream))
+	}
+
+	flagTxSubjects = startCmd.Flags().String("tx-subjects", os.Getenv(TX_SUBJECTS), fmt.Sprintf("Publish transactions to tx (%s), to tx.{message type} (%s) or to both (%s)", osmosis.TxSubjectsSingle, osmosis.TxSubjectsTypes, osmosis.TxSubjectsBoth))
+
+	poolSubjects, _ := strconv.ParseBool(os.Getenv(POOL_SUBJECTS))
+	flagPoolSubjects = startCmd.Flags().Bool("pool-subjects", poolSubjects, "Also publish every pool to volume.pool.{id} and state.pool.{id}")
+
+	query, _ := strconv.ParseBool(os.Getenv(QUERY))
+	flagQuery = startCmd.Flags().Bool("query", query, "Serve request/reply queries on query.{name}")
 
 	flagPricesSubject = startCmd.Flags().String("prices-subject", os.Getenv(PRICES_SUBJECT), "Subject for prices feed to subscribe to")
 
-	flagSocketAddr = startCmd.Flags().String("socket", os.Getenv(SOCKET_ADDR), "Socket addr to publish data")
+	flagSocketAddr = startCmd.Flags().String("socket", os.Getenv(SOCKET_ADDR), "Unix socket to mirror transactions to (same as --sinks unix://{socket}?subject=tx, or subject=tx.> with --tx-subjects types)")
+	socketListen, _ := strconv.ParseBool(os.Getenv(SOCKET_LISTEN))
+	flagSocketListen = startCmd.Flags().Bool("socket-listen", socketListen, "Listen on --socket and accept many clients instead of connecting to it")
+	flagSocketFraming = startCmd.Flags().String("socket-framing", os.Getenv(SOCKET_FRAMING), fmt.Sprintf("Framing of --socket messages (%s)", strings.Join(dtlWithSocket.Framings, ", ")))
+	flagSinks = startCmd.Flags().String("sinks", os.Getenv(SINKS), "Sinks to mirror published messages to, e.g. unix:///socket/osmosis.sock?subject=tx (separated by comma)")
+	flagSinkConfig = startCmd.Flags().String("sink-config", os.Getenv(SINK_CONFIG), "JSON file with the list of sinks")
 
 	pools := SplitAndTrimEmpty(os.Getenv(OSMOSIS_POOLS), ",", " \t\r\n\b")
 	dp := make([]int64, len(pools))
@@ -33,6 +33,20 @@ func SplitAndTrimEmpty(s, sep, cutset string) []string {
 	return nonEmptyStrings
 }
 
+// ParseHeaders parses a comma separated list of key=value pairs.
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, kv := range SplitAndTrimEmpty(s, ",", " \t\r\n\b") {
+		key, value, found := strings.Cut(kv, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("header %q is not in key=value format", kv)
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
 func setDefault(field string, value string) {
 	if os.Getenv(field) == "" {
 		os.Setenv(field, value)
@@ -1,123 +1,279 @@
 #!/bin/sh
 
-CMD="./osmosis-publisher"
+set -- ./osmosis-publisher
 
 if [ ! -z "$CA_CERT" ]; then
-  CMD="$CMD --ca-cert $CA_CERT"
+  set -- "$@" --ca-cert "$CA_CERT"
 fi
 
 if [ ! -z "$CLIENT_CERT" ]; then
-  CMD="$CMD --client-cert $CLIENT_CERT"
+  set -- "$@" --client-cert "$CLIENT_CERT"
 fi
 
 if [ ! -z "$CLIENT_KEY" ]; then
-  CMD="$CMD --client-key $CLIENT_KEY"
+  set -- "$@" --client-key "$CLIENT_KEY"
 fi
 
 if [ ! -z "$DB_HOST" ]; then
-  CMD="$CMD --db-host $DB_HOST"
+  set -- "$@" --db-host "$DB_HOST"
 fi
 
 if [ ! -z "$DB_NAME" ]; then
-  CMD="$CMD --db-name $DB_NAME"
+  set -- "$@" --db-name "$DB_NAME"
 fi
 
 if [ ! -z "$DB_PASSW" ]; then
-  CMD="$CMD --db-passw $DB_PASSW"
+  set -- "$@" --db-passw "$DB_PASSW"
 fi
 
 if [ ! -z "$DB_PORT" ]; then
-  CMD="$CMD --db-port $DB_PORT"
+  set -- "$@" --db-port "$DB_PORT"
 fi
 
 if [ ! -z "$DB_USER" ]; then
-  CMD="$CMD --db-user $DB_USER"
+  set -- "$@" --db-user "$DB_USER"
 fi
 
 if [ ! -z "$IDENTITY" ]; then
-  CMD="$CMD --identity $IDENTITY"
+  set -- "$@" --identity "$IDENTITY"
 fi
 
 if [ ! -z "$NATS_URL" ]; then
-  CMD="$CMD --nats-url $NATS_URL"
+  set -- "$@" --nats-url "$NATS_URL"
 fi
 
 if [ ! -z "$NATS_ACC_NKEY" ]; then
-  CMD="$CMD --nats-acc-nkey $NATS_ACC_NKEY"
+  set -- "$@" --nats-acc-nkey "$NATS_ACC_NKEY"
 fi
 
 if [ ! -z "$NATS_CREDS" ]; then
-  CMD="$CMD --nats-creds $NATS_CREDS"
+  set -- "$@" --nats-creds "$NATS_CREDS"
 fi
 
 if [ ! -z "$NATS_JWT" ]; then
-  CMD="$CMD --nats-jwt $NATS_JWT"
+  set -- "$@" --nats-jwt "$NATS_JWT"
 fi
 
 if [ ! -z "$NATS_NKEY" ]; then
-  CMD="$CMD --nats-nkey $NATS_NKEY"
+  set -- "$@" --nats-nkey "$NATS_NKEY"
 fi
 
 if [ ! -z "$NATS_SUB_URL" ]; then
-  CMD="$CMD --nats-sub-url $NATS_SUB_URL"
+  set -- "$@" --nats-sub-url "$NATS_SUB_URL"
 fi
 
 if [ ! -z "$NATS_SUB_CREDS" ]; then
-  CMD="$CMD --nats-sub-creds $NATS_SUB_CREDS"
+  set -- "$@" --nats-sub-creds "$NATS_SUB_CREDS"
 fi
 
 if [ ! -z "$NATS_SUB_JWT" ]; then
-  CMD="$CMD --nats-sub-jwt $NATS_SUB_JWT"
+  set -- "$@" --nats-sub-jwt "$NATS_SUB_JWT"
 fi
 
 if [ ! -z "$NATS_SUB_NKEY" ]; then
-  CMD="$CMD --nats-sub-nkey $NATS_SUB_NKEY"
+  set -- "$@" --nats-sub-nkey "$NATS_SUB_NKEY"
 fi
 
 if [ ! -z "$PREFIX" ]; then
-  CMD="$CMD --prefix $PREFIX"
+  set -- "$@" --prefix "$PREFIX"
 fi
 
 if [ ! -z "$TELEMETRY_PERIOD" ]; then
-  CMD="$CMD --telemetry-period $TELEMETRY_PERIOD"
+  set -- "$@" --telemetry-period "$TELEMETRY_PERIOD"
 fi
 
 if [ ! -z "$PROMETHEUS_EXPORT" ]; then
-  CMD="$CMD --prometheus-export $PROMETHEUS_EXPORT"
+  set -- "$@" --prometheus-export "$PROMETHEUS_EXPORT"
+fi
+
+if [ ! -z "$API_ADDR" ]; then
+  set -- "$@" --api "$API_ADDR"
 fi
 
 if [ ! -z "$VERBOSE" ]; then
-  CMD="$CMD --verbose"
+  set -- "$@" --verbose
 fi
 
-CMD="$CMD start "
+set -- "$@" start
 
 if [ ! -z "$APP_API" ]; then
-  CMD="$CMD --app-api $APP_API"
+  set -- "$@" --app-api "$APP_API"
 fi
 
 if [ ! -z "$BLOCKS_TO_INDEX" ]; then
-  CMD="$CMD --blocks-to-index $BLOCKS_TO_INDEX"
+  set -- "$@" --blocks-to-index "$BLOCKS_TO_INDEX"
 fi
 
 if [ ! -z "$GRPC_API" ]; then
-  CMD="$CMD --grpc-api $GRPC_API"
+  set -- "$@" --grpc-api "$GRPC_API"
+fi
+
+if [ ! -z "$GRPC_ARCHIVE_API" ]; then
+  set -- "$@" --grpc-archive-api "$GRPC_ARCHIVE_API"
+fi
+
+if [ ! -z "$ARCHIVE_DEPTH" ]; then
+  set -- "$@" --archive-depth "$ARCHIVE_DEPTH"
+fi
+
+if [ "$GRPC_TLS" = "true" ]; then
+  set -- "$@" --grpc-tls
+fi
+
+if [ ! -z "$GRPC_TLS_CA" ]; then
+  set -- "$@" --grpc-tls-ca "$GRPC_TLS_CA"
+fi
+
+if [ ! -z "$GRPC_TLS_CERT" ]; then
+  set -- "$@" --grpc-tls-cert "$GRPC_TLS_CERT"
+fi
+
+if [ ! -z "$GRPC_TLS_KEY" ]; then
+  set -- "$@" --grpc-tls-key "$GRPC_TLS_KEY"
+fi
+
+if [ ! -z "$GRPC_TLS_SERVER_NAME" ]; then
+  set -- "$@" --grpc-tls-server-name "$GRPC_TLS_SERVER_NAME"
+fi
+
+if [ ! -z "$GRPC_HEADERS" ]; then
+  set -- "$@" --grpc-headers "$GRPC_HEADERS"
+fi
+
+if [ ! -z "$TENDERMINT_HEADERS" ]; then
+  set -- "$@" --tendermint-headers "$TENDERMINT_HEADERS"
+fi
+
+if [ ! -z "$TENDERMINT_USER" ]; then
+  set -- "$@" --tendermint-user "$TENDERMINT_USER"
+fi
+
+if [ ! -z "$TENDERMINT_PASSWORD" ]; then
+  set -- "$@" --tendermint-password "$TENDERMINT_PASSWORD"
+fi
+
+if [ ! -z "$EVENT_QUERIES" ]; then
+  set -- "$@" --event-queries "$EVENT_QUERIES"
+fi
+
+if [ ! -z "$BLOCK_DEADLINE" ]; then
+  set -- "$@" --block-deadline "$BLOCK_DEADLINE"
+fi
+
+if [ ! -z "$MAX_CATCHUP_BLOCKS" ]; then
+  set -- "$@" --max-catchup-blocks "$MAX_CATCHUP_BLOCKS"
+fi
+
+if [ ! -z "$SPILL_DIR" ]; then
+  set -- "$@" --spill-dir "$SPILL_DIR"
+fi
+
+if [ ! -z "$SPILL_MAX_BYTES" ]; then
+  set -- "$@" --spill-max-bytes "$SPILL_MAX_BYTES"
+fi
+
+if [ ! -z "$OUTBOX_DIR" ]; then
+  set -- "$@" --outbox-dir "$OUTBOX_DIR"
+fi
+
+if [ ! -z "$OUTBOX_MAX_BYTES" ]; then
+  set -- "$@" --outbox-max-bytes "$OUTBOX_MAX_BYTES"
+fi
+
+if [ ! -z "$OUTBOX_MAX_AGE" ]; then
+  set -- "$@" --outbox-max-age "$OUTBOX_MAX_AGE"
+fi
+
+if [ ! -z "$ENCODING" ]; then
+  set -- "$@" --encoding "$ENCODING"
+fi
+
+if [ ! -z "$STREAM_BLOCK" ]; then
+  set -- "$@" "--stream-block=$STREAM_BLOCK"
+fi
+
+if [ ! -z "$STREAM_TX" ]; then
+  set -- "$@" "--stream-tx=$STREAM_TX"
+fi
+
+if [ ! -z "$STREAM_MEMPOOL" ]; then
+  set -- "$@" "--stream-mempool=$STREAM_MEMPOOL"
+fi
+
+if [ ! -z "$STREAM_STATE_POOLS" ]; then
+  set -- "$@" "--stream-state-pools=$STREAM_STATE_POOLS"
+fi
+
+if [ ! -z "$STREAM_VOLUME_POOL" ]; then
+  set -- "$@" "--stream-volume-pool=$STREAM_VOLUME_POOL"
+fi
+
+if [ ! -z "$STREAM_SWAPS" ]; then
+  set -- "$@" "--stream-swaps=$STREAM_SWAPS"
+fi
+
+if [ ! -z "$TX_SUBJECTS" ]; then
+  set -- "$@" --tx-subjects "$TX_SUBJECTS"
+fi
+
+if [ "$POOL_SUBJECTS" = "true" ]; then
+  set -- "$@" --pool-subjects
+fi
+
+if [ "$QUERY" = "true" ]; then
+  set -- "$@" --query
+fi
+
+if [ ! -z "$COMPRESSION" ]; then
+  set -- "$@" --compression "$COMPRESSION"
+fi
+
+if [ ! -z "$MAX_PAYLOAD" ]; then
+  set -- "$@" --max-payload "$MAX_PAYLOAD"
+fi
+
+if [ "$JETSTREAM" = "true" ]; then
+  set -- "$@" --jetstream
+fi
+
+if [ ! -z "$RPC_CONCURRENCY" ]; then
+  set -- "$@" --rpc-concurrency "$RPC_CONCURRENCY"
+fi
+
+if [ ! -z "$RPC_RATE_LIMIT" ]; then
+  set -- "$@" --rpc-rate-limit "$RPC_RATE_LIMIT"
 fi
 
 if [ ! -z "$PUBLISHER_NAME" ]; then
-  CMD="$CMD --publisher-name $PUBLISHER_NAME"
+  set -- "$@" --publisher-name "$PUBLISHER_NAME"
 fi
 
 if [ ! -z "$POOL_IDS" ]; then
-  CMD="$CMD --pool-ids $POOL_IDS"
+  set -- "$@" --pool-ids "$POOL_IDS"
 fi
 
 if [ ! -z "$PRICES_SUBJECT" ]; then
-  CMD="$CMD --prices-subject $PRICES_SUBJECT"
+  set -- "$@" --prices-subject "$PRICES_SUBJECT"
+fi
+
+if [ "$SOCKET_LISTEN" = "true" ]; then
+  set -- "$@" --socket-listen
+fi
+
+if [ ! -z "$SOCKET_FRAMING" ]; then
+  set -- "$@" --socket-framing "$SOCKET_FRAMING"
+fi
+
+if [ ! -z "$SINKS" ]; then
+  set -- "$@" --sinks "$SINKS"
+fi
+
+if [ ! -z "$SINK_CONFIG" ]; then
+  set -- "$@" --sink-config "$SINK_CONFIG"
 fi
 
 if [ ! -z "$TENDERMINT_API" ]; then
-  CMD="$CMD --tendermint-api $TENDERMINT_API"
+  set -- "$@" --tendermint-api "$TENDERMINT_API"
 fi
 
-exec $CMD
+exec "$@"
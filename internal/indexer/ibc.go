@@ -6,7 +6,10 @@ import (
 
 func (d *Indexer) DenomTrace(ibc string) (ibctypes.DenomTrace, error) {
 	// Check if the denomStr is in the cache
-	if trace, found := d.ibcTraceCache[ibc]; found {
+	d.ibcTraceMu.RLock()
+	trace, found := d.ibcTraceCache[ibc]
+	d.ibcTraceMu.RUnlock()
+	if found {
 		// If found, return the trace
 		return trace, nil
 	}
@@ -18,7 +21,9 @@ func (d *Indexer) DenomTrace(ibc string) (ibctypes.DenomTrace, error) {
 	}
 
 	// Update the cache with the new denom trace
+	d.ibcTraceMu.Lock()
 	d.ibcTraceCache[ibc] = trace
+	d.ibcTraceMu.Unlock()
 	d.repo.SaveIBCDenom(trace)
 
 	return trace, nil
@@ -41,9 +46,11 @@ func (d *Indexer) loadDenomTraceCache() bool {
 		return false
 	}
 
+	d.ibcTraceMu.Lock()
 	for _, trace := range traces {
 		d.ibcTraceCache[trace.IBCDenom()] = trace
 	}
+	d.ibcTraceMu.Unlock()
 	d.logger.Info("SYNC: IBC Denoms loaded", "len(traces)", len(traces))
 
 	return true
@@ -61,9 +68,11 @@ func (d *Indexer) preHeatDenomTraceCache() {
 		return
 	}
 
+	d.ibcTraceMu.Lock()
 	for _, trace := range traces {
 		d.ibcTraceCache[trace.IBCDenom()] = trace
 	}
+	d.ibcTraceMu.Unlock()
 
 	d.logger.Info("SYNC: IBC Denoms fetched", "len(traces)", len(traces))
 }
@@ -0,0 +1,57 @@
+package indexer
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+)
+
+type traceRPC struct {
+	ExpectedRPC
+}
+
+func (traceRPC) DenomTrace(ibc string) (ibctypes.DenomTrace, error) {
+	return ibctypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: ibc}, nil
+}
+
+type traceRepository struct {
+	repository.Repository
+}
+
+func (traceRepository) SaveIBCDenom(ibctypes.DenomTrace) error {
+	return nil
+}
+
+func TestIndexer_DenomTraceConcurrent(t *testing.T) {
+	d := &Indexer{
+		logger:        slog.Default(),
+		rpc:           traceRPC{},
+		repo:          traceRepository{},
+		ibcTraceCache: make(map[string]ibctypes.DenomTrace),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				denom := fmt.Sprintf("ibc/%d", j%10)
+				if trace, err := d.DenomTrace(denom); err != nil || trace.BaseDenom != denom {
+					t.Errorf("DenomTrace(%s) = %v, %v", denom, trace, err)
+					return
+				}
+				d.GetStatus()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := d.GetStatus()["indexer_ibc_tokens"]; got != "10" {
+		t.Errorf("cached traces = %s, want 10", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -35,6 +36,8 @@ type ExpectedRPC interface {
 	PoolsTotalLiquidityAt(height int64, ids ...uint64) ([]types.PoolLiquidity, error)
 	PoolsVolumeAt(height int64, ids ...uint64) ([]types.PoolVolume, error)
 	Subscribe(eventName string, handle func(events <-chan ctypes.ResultEvent) error) error
+	// HasStateAt reports whether the node can serve state at the given height.
+	HasStateAt(height int64) (bool, error)
 }
 
 type Indexer struct {
@@ -45,6 +48,7 @@ type Indexer struct {
 	rpc    ExpectedRPC
 	logger *slog.Logger
 
+	ibcTraceMu    sync.RWMutex
 	ibcTraceCache map[string]IBCTypes.DenomTrace
 	ibcMisses     atomic.Uint64
 
@@ -59,6 +63,8 @@ type Indexer struct {
 	blocksPerHour      atomic.Int64
 	lastBlockHeight    uint64
 	lastBlockTimestamp atomic.Int64
+	minAvailableHeight atomic.Uint64
+	blocks             uint64
 
 	verbose bool
 }
@@ -81,6 +87,7 @@ func New(ctx context.Context, cancel context.CancelCauseFunc, group *errgroup.Gr
 		poolIdsToMonitor: make([]uint64, len(poolIds)),
 		ibcTraceCache:    make(map[string]IBCTypes.DenomTrace),
 		verbose:          verbose,
+		blocks:           blocks,
 	}
 	ret.blocksPerHour.Store(DefaultBlocksPerHour)
 	block, err := rpc.BlockAt(0)
@@ -91,10 +98,18 @@ func New(ctx context.Context, cancel context.CancelCauseFunc, group *errgroup.Gr
 	ret.currentBlockTime.Store(block.Time.UnixNano())
 
 	copy(ret.poolIdsToMonitor, poolIds)
+	if err := ret.probeMinAvailableHeight(blocks); err != nil {
+		ret.logger.Warn("SYNC: Failed probing node for available heights", "err", err)
+	}
 	ret.preHeatDenomTraceCache()
 	ret.preHeatPools(blocks)
 	ret.preHeatPrices(blocks)
 
+	// Without a window there is nothing to sync, and pruning would delete the stored pools and prices.
+	if blocks == 0 {
+		ret.logger.Info("SYNC: No blocks to index, pools are not synced nor pruned")
+		return ret, nil
+	}
 	group.Go(func() error {
 		return ret.handleSyncing(blocks)
 	})
@@ -103,13 +118,17 @@ func New(ctx context.Context, cancel context.CancelCauseFunc, group *errgroup.Gr
 }
 
 func (d *Indexer) GetStatus() map[string]string {
+	d.ibcTraceMu.RLock()
+	ibcTokens := len(d.ibcTraceCache)
+	d.ibcTraceMu.RUnlock()
 	return map[string]string{
-		"indexer_errors":              strconv.FormatUint(d.errCounter.Load(), 10),
-		"indexer_blocks_per_hour":     strconv.FormatInt(d.blocksPerHour.Load(), 10),
-		"indexer_ibc_tokens":          strconv.Itoa(len(d.ibcTraceCache)),
-		"indexer_ibc_cache_misses":    strconv.FormatUint(d.ibcMisses.Load(), 10),
-		"indexer_pool_current_height": strconv.FormatUint(d.currentBlockHeight.Load(), 10),
-		"indexer_pool_sync_count":     strconv.Itoa(len(d.syncHeights)),
+		"indexer_errors":               strconv.FormatUint(d.errCounter.Load(), 10),
+		"indexer_blocks_per_hour":      strconv.FormatInt(d.blocksPerHour.Load(), 10),
+		"indexer_ibc_tokens":           strconv.Itoa(ibcTokens),
+		"indexer_ibc_cache_misses":     strconv.FormatUint(d.ibcMisses.Load(), 10),
+		"indexer_pool_current_height":  strconv.FormatUint(d.currentBlockHeight.Load(), 10),
+		"indexer_pool_sync_count":      strconv.Itoa(len(d.syncHeights)),
+		"indexer_min_available_height": strconv.FormatUint(d.minAvailableHeight.Load(), 10),
 		// "indexer_pool_errors":       strconv.FormatUint(d.poolErrors.Load(), 10),
 		// "indexer_pool_misses":       strconv.FormatUint(d.poolMisses.Load(), 10),
 	}
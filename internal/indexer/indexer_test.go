@@ -0,0 +1,81 @@
+package indexer
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	tmtypes "github.com/cometbft/cometbft/types"
+	IBCTypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+	"golang.org/x/sync/errgroup"
+)
+
+type startupRPC struct {
+	ExpectedRPC
+}
+
+func (startupRPC) BlockAt(height int64) (*tmtypes.Block, error) {
+	return &tmtypes.Block{Header: tmtypes.Header{Height: 1000, Time: time.Now()}}, nil
+}
+
+func (startupRPC) HasStateAt(height int64) (bool, error) {
+	return true, nil
+}
+
+func (startupRPC) DenomTraces() ([]IBCTypes.DenomTrace, error) {
+	return nil, nil
+}
+
+type startupRepository struct {
+	repository.Repository
+	pricesFrom time.Time
+	pruned     chan string
+}
+
+func (r *startupRepository) IBCDenomAll() []IBCTypes.DenomTrace {
+	return nil
+}
+
+func (r *startupRepository) PoolsRange(minHeight, maxHeight, poolId uint64) ([]repository.Pool, error) {
+	return nil, nil
+}
+
+func (r *startupRepository) TokenPricesRange(min, max time.Time, denom string) ([]repository.TokenPrice, error) {
+	r.pricesFrom = min
+	return nil, nil
+}
+
+func (r *startupRepository) PrunePools(height uint64) (int, error) {
+	r.pruned <- "pools"
+	return 0, nil
+}
+
+func (r *startupRepository) PruneTokenPrices(timestamp time.Time) (int, error) {
+	r.pruned <- "prices"
+	return 0, nil
+}
+
+func TestNew_noBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	group, ctx := errgroup.WithContext(ctx)
+	repo := &startupRepository{pruned: make(chan string, 10)}
+
+	if _, err := New(ctx, cancel, group, slog.Default(), repo, startupRPC{}, nil, 0, false); err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if age := time.Since(repo.pricesFrom); age < preHeatPricesDuration {
+		t.Errorf("prices loaded from %v ago, want at least %v", age, preHeatPricesDuration)
+	}
+
+	// The sync loop prunes right after it starts, so give it the chance to.
+	select {
+	case what := <-repo.pruned:
+		t.Errorf("%s pruned without blocks to index", what)
+	case <-time.After(100 * time.Millisecond):
+	}
+	cancel(nil)
+	group.Wait()
+}
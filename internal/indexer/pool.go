@@ -2,9 +2,11 @@ package indexer
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 
+	"github.com/synternet/osmosis-publisher/pkg/indexer"
 	"github.com/synternet/osmosis-publisher/pkg/repository"
 	"github.com/synternet/osmosis-publisher/pkg/types"
 )
@@ -112,81 +114,104 @@ func (d *Indexer) poolsPrune(minHeight uint64) {
 	d.repo.PrunePools(minHeight)
 }
 
+// PoolStatusesAt returns pool statuses at height. Pools missing from the cache are fetched from the node in a single batch.
+// Statuses of the pools that failed are left empty and their errors are joined.
 func (d *Indexer) PoolStatusesAt(height uint64, poolId ...uint64) ([]types.PoolStatus, uint64, error) {
 	if height == 0 {
 		height = d.currentBlockHeight.Load()
 	}
 
-	poolStatuses := make([]types.PoolStatus, len(poolId))
-	errArr := make([]error, 0, len(poolId))
-	for i, id := range poolId {
-		ps, _, err := d.PoolStatusAt(height, id)
-		if err != nil {
-			errArr = append(errArr, err)
-			continue
-		}
-		poolStatuses[i] = ps
+	pools, err := d.getPools(height, true, poolId...)
+	if err != nil {
+		d.logger.Error("SYNC: PoolStatusesAt failed", "height", height, "err", err)
 	}
-
-	return poolStatuses, height, errors.Join(errArr...)
+	return poolStatuses(height, pools, poolId), height, err
 }
 
-func (d *Indexer) PoolStatusAt(height, poolId uint64) (types.PoolStatus, uint64, error) {
+// QueryPoolStatusesAt is the same as PoolStatusesAt, but it serves only the heights of the indexed window
+// and the pools fetched from the node are neither cached nor stored.
+func (d *Indexer) QueryPoolStatusesAt(height uint64, poolId ...uint64) ([]types.PoolStatus, uint64, error) {
+	current := d.currentBlockHeight.Load()
 	if height == 0 {
-		height = d.currentBlockHeight.Load()
+		height = current
 	}
-
-	poolStatus := types.PoolStatus{
-		PoolId: poolId,
+	lo := d.minAvailableHeight.Load()
+	if current > d.blocks {
+		lo = max(lo, current-d.blocks)
 	}
-
-	pool, err := d.getPool(height, poolId)
-	if err != nil {
-		d.logger.Error("SYNC: PoolStatusAt failed", "poolId", poolId, "height", height, "err", err)
-		return poolStatus, height, err
-	}
-
-	poolStatus.TotalLiquidity = pool.Liquidity
-	poolStatus.Volumes = []types.PoolStatusVolumeAt{
-		{
-			BlockHeight: int64(height),
-			Volume:      pool.Volume,
-		},
+	if height < lo || height > current {
+		return nil, height, fmt.Errorf("%w: height %d is outside of %d-%d", indexer.ErrHeightNotIndexed, height, lo, current)
 	}
 
-	return poolStatus, height, nil
+	pools, err := d.getPools(height, false, poolId...)
+	return poolStatuses(height, pools, poolId), height, err
 }
 
-func (d *Indexer) getPool(height, poolId uint64) (repository.Pool, error) {
-	if height == 0 {
-		height = d.currentBlockHeight.Load()
-	}
-	pool, found := d.pools.Get(height, poolId)
-	if found {
-		return pool, nil
+// poolStatuses returns the statuses of the pools in the order of poolIds. Statuses of missing pools are left empty.
+func poolStatuses(height uint64, pools map[uint64]repository.Pool, poolId []uint64) []types.PoolStatus {
+	statuses := make([]types.PoolStatus, len(poolId))
+	for i, id := range poolId {
+		pool, ok := pools[id]
+		if !ok {
+			continue
+		}
+		statuses[i] = types.PoolStatus{
+			PoolId:         id,
+			TotalLiquidity: pool.Liquidity,
+			Volumes: []types.PoolStatusVolumeAt{
+				{
+					BlockHeight: int64(height),
+					Volume:      pool.Volume,
+				},
+			},
+		}
 	}
+	return statuses
+}
 
-	liquidity, err := d.rpc.PoolsTotalLiquidityAt(int64(height), poolId)
-	if err != nil {
-		return pool, err
+// getPools returns the pools at height from the cache and fetches the missing ones with one liquidity and one volume query.
+// Fetched pools are cached and stored if persist is set. Pools that could not be fetched are omitted and their errors are joined.
+func (d *Indexer) getPools(height uint64, persist bool, poolIds ...uint64) (map[uint64]repository.Pool, error) {
+	pools := make(map[uint64]repository.Pool, len(poolIds))
+	missing := make([]uint64, 0, len(poolIds))
+	for _, id := range poolIds {
+		if pool, found := d.pools.Get(height, id); found {
+			pools[id] = pool
+		} else {
+			missing = append(missing, id)
+		}
 	}
-	volume, err := d.rpc.PoolsVolumeAt(int64(height), poolId)
-	if err != nil {
-		return pool, err
+	if len(missing) == 0 {
+		return pools, nil
 	}
 
-	pool = repository.Pool{
-		Height:    height,
-		PoolId:    poolId,
-		Liquidity: liquidity[0].Liquidity,
-		Volume:    volume[0].Volume,
-	}
+	liquidity, errLiquidity := d.rpc.PoolsTotalLiquidityAt(int64(height), missing...)
+	volume, errVolume := d.rpc.PoolsVolumeAt(int64(height), missing...)
+	errArr := []error{errLiquidity, errVolume}
 
-	d.pools.Set(pool)
-	err = d.repo.SavePool(pool)
-	if err != nil {
-		return pool, err
+	fetched := make(map[uint64]repository.Pool, len(liquidity))
+	for _, l := range liquidity {
+		fetched[l.PoolId] = repository.Pool{
+			Height:    height,
+			PoolId:    l.PoolId,
+			Liquidity: l.Liquidity,
+		}
+	}
+	for _, v := range volume {
+		pool, ok := fetched[v.PoolId]
+		if !ok {
+			continue
+		}
+		pool.Volume = v.Volume
+		pools[v.PoolId] = pool
+		if !persist {
+			continue
+		}
+		d.pools.Set(pool)
+		if err := d.repo.SavePool(pool); err != nil {
+			errArr = append(errArr, err)
+		}
 	}
 
-	return pool, nil
+	return pools, errors.Join(errArr...)
 }
@@ -0,0 +1,127 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/synternet/osmosis-publisher/pkg/indexer"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+	"github.com/synternet/osmosis-publisher/pkg/types"
+)
+
+type poolsRPC struct {
+	ExpectedRPC
+	failed uint64
+	calls  [][]uint64
+}
+
+func (r *poolsRPC) PoolsTotalLiquidityAt(height int64, ids ...uint64) ([]types.PoolLiquidity, error) {
+	r.calls = append(r.calls, ids)
+	var ret []types.PoolLiquidity
+	for _, id := range ids {
+		if id == r.failed {
+			continue
+		}
+		ret = append(ret, types.PoolLiquidity{PoolId: id, Liquidity: sdk.NewCoins(sdk.NewInt64Coin("uosmo", int64(id)))})
+	}
+	if r.failed != 0 {
+		return ret, fmt.Errorf("failed retrieving pool liquidity %d", r.failed)
+	}
+	return ret, nil
+}
+
+func (r *poolsRPC) PoolsVolumeAt(height int64, ids ...uint64) ([]types.PoolVolume, error) {
+	r.calls = append(r.calls, ids)
+	var ret []types.PoolVolume
+	for _, id := range ids {
+		ret = append(ret, types.PoolVolume{PoolId: id, Volume: sdk.NewCoins(sdk.NewInt64Coin("uosmo", int64(id*10)))})
+	}
+	return ret, nil
+}
+
+type poolsRepository struct {
+	repository.Repository
+	saved []uint64
+}
+
+func (r *poolsRepository) SavePool(pool repository.Pool) error {
+	r.saved = append(r.saved, pool.PoolId)
+	return nil
+}
+
+func TestIndexer_PoolStatusesAt(t *testing.T) {
+	rpc := &poolsRPC{failed: 3}
+	repo := &poolsRepository{}
+	d := &Indexer{
+		logger: slog.Default(),
+		rpc:    rpc,
+		repo:   repo,
+		pools:  PoolMap{pools: make(map[uint64]map[uint64]repository.Pool)},
+	}
+	d.pools.Set(repository.Pool{Height: 100, PoolId: 1})
+
+	statuses, height, err := d.PoolStatusesAt(100, 1, 2, 3, 4)
+	if err == nil || height != 100 {
+		t.Fatalf("PoolStatusesAt() = %d, %v, want height 100 and the error of pool 3", height, err)
+	}
+
+	// Missing pools of a height are fetched with a single call per query.
+	if fmt.Sprint(rpc.calls) != "[[2 3 4] [2 3 4]]" {
+		t.Errorf("calls = %v, want one liquidity and one volume call for pools 2, 3 and 4", rpc.calls)
+	}
+	if fmt.Sprint(repo.saved) != "[2 4]" {
+		t.Errorf("saved = %v, want [2 4]", repo.saved)
+	}
+	wantIds := []uint64{1, 2, 0, 4}
+	for i, ps := range statuses {
+		if ps.PoolId != wantIds[i] {
+			t.Errorf("statuses[%d].PoolId = %d, want %d", i, ps.PoolId, wantIds[i])
+		}
+	}
+	if got := statuses[3].Volumes[0].Volume.AmountOf("uosmo").Int64(); got != 40 {
+		t.Errorf("volume of pool 4 = %d, want 40", got)
+	}
+
+	rpc.calls = nil
+	if _, _, err := d.PoolStatusesAt(100, 1, 2, 4); err != nil || len(rpc.calls) != 0 {
+		t.Errorf("PoolStatusesAt() of cached pools = %v, calls = %v, want no calls", err, rpc.calls)
+	}
+}
+
+func TestIndexer_QueryPoolStatusesAt(t *testing.T) {
+	rpc := &poolsRPC{}
+	repo := &poolsRepository{}
+	d := &Indexer{
+		logger: slog.Default(),
+		rpc:    rpc,
+		repo:   repo,
+		blocks: 50,
+		pools:  PoolMap{pools: make(map[uint64]map[uint64]repository.Pool)},
+	}
+	d.currentBlockHeight.Store(200)
+	d.minAvailableHeight.Store(160)
+
+	for _, height := range []uint64{100, 159, 201} {
+		if _, _, err := d.QueryPoolStatusesAt(height, 1); !errors.Is(err, indexer.ErrHeightNotIndexed) {
+			t.Errorf("QueryPoolStatusesAt(%d) error = %v, want ErrHeightNotIndexed", height, err)
+		}
+	}
+	if len(rpc.calls) != 0 {
+		t.Errorf("calls = %v, want no calls outside of the window", rpc.calls)
+	}
+
+	statuses, height, err := d.QueryPoolStatusesAt(0, 1, 2)
+	if err != nil || height != 200 || len(statuses) != 2 || statuses[1].PoolId != 2 {
+		t.Fatalf("QueryPoolStatusesAt(0) = %v, %d, %v, want pools 1 and 2 at 200", statuses, height, err)
+	}
+	// Queried pools are neither cached nor stored.
+	if len(repo.saved) != 0 {
+		t.Errorf("saved = %v, want none", repo.saved)
+	}
+	if d.pools.Has(200, 1) {
+		t.Errorf("pool 1 is cached, want not cached")
+	}
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/synternet/osmosis-publisher/pkg/repository"
 )
 
+// preHeatPricesDuration is the shortest range of prices loaded from the database at startup.
+// It covers the age of prices that swaps and price queries are valued with.
+const preHeatPricesDuration = 24 * time.Hour
+
 var tokenMapping = map[string]float64{
 	"OSMO": 1e-6,
 	"ATOM": 1e-6,
@@ -131,8 +135,11 @@ func (p *PriceMap) SortToken(denom string) {
 	)
 }
 
+// preHeatPrices loads the prices of the blocks to index, but at least of the last preHeatPricesDuration,
+// so that prices are available without pool syncing too.
 func (d *Indexer) preHeatPrices(blocks uint64) {
-	min, max := time.Now().Add(-(time.Hour*time.Duration(blocks))/time.Duration(d.blocksPerHour.Load())), time.Now()
+	window := max((time.Hour*time.Duration(blocks))/time.Duration(d.blocksPerHour.Load()), preHeatPricesDuration)
+	min, max := time.Now().Add(-window), time.Now()
 	prices, err := d.repo.TokenPricesRange(min, max, "")
 	if err != nil {
 		d.logger.Error("SYNC: Failed fetching prices", "from", min, "to", max, "err", err)
@@ -165,6 +172,15 @@ func (d *Indexer) pricesPrune(minHeight uint64) {
 	d.repo.PruneTokenPrices(minLastUpdated)
 }
 
+// PriceAt estimates the price from the cached price feed.
+func (d *Indexer) PriceAt(timestamp time.Time, denom string) (float64, time.Duration, bool) {
+	if len(d.prices.Nearest(timestamp, denom)) == 0 {
+		return 0, 0, false
+	}
+	value, estimationError := d.prices.Estimate(timestamp, denom)
+	return value, estimationError, true
+}
+
 func convertToMicroToken(token string, value float64) (string, float64, bool) {
 	if factor, found := tokenMapping[token]; found {
 		uToken := fmt.Sprintf("u%s", strings.ToLower(token))
@@ -0,0 +1,74 @@
+package indexer
+
+// probeMinAvailableHeight finds the lowest height within the indexing window for which the node can still serve state.
+// It assumes the node keeps a contiguous range of states up to the latest height. The whole window is probed every time,
+// since another endpoint may serve heights a previous probe could not reach. If no height is available, the previous value is kept.
+func (d *Indexer) probeMinAvailableHeight(blocks uint64) error {
+	current := d.currentBlockHeight.Load()
+	lo := uint64(1)
+	if current > blocks {
+		lo = current - blocks
+	}
+
+	minHeight, ok, err := d.lowestStateHeight(lo, current)
+	if err != nil {
+		d.errCounter.Add(1)
+		return err
+	}
+	if !ok {
+		d.logger.Warn("SYNC: Node serves no state within blocks to index, keeping sync window", "from", lo, "to", current, "min_available_height", d.minAvailableHeight.Load())
+		return nil
+	}
+
+	old := d.minAvailableHeight.Swap(minHeight)
+	if current > blocks && minHeight > current-blocks && old != minHeight {
+		d.logger.Warn(
+			"SYNC: Node pruning window is smaller than blocks to index, clamping sync window",
+			"min_available_height", minHeight,
+			"available_blocks", current-minHeight,
+			"blocks_to_index", blocks,
+		)
+	}
+
+	return nil
+}
+
+// lowestStateHeight performs a binary search for the lowest height in [lo, hi] the node has state for.
+// Will return false if none of the heights are available.
+func (d *Indexer) lowestStateHeight(lo, hi uint64) (uint64, bool, error) {
+	available, err := d.rpc.HasStateAt(int64(lo))
+	if err != nil {
+		return 0, false, err
+	}
+	if available {
+		return lo, true, nil
+	}
+	if lo >= hi {
+		return 0, false, nil
+	}
+
+	available, err = d.rpc.HasStateAt(int64(hi))
+	if err != nil || !available {
+		return 0, false, err
+	}
+
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		available, err := d.rpc.HasStateAt(int64(mid))
+		if err != nil {
+			return 0, false, err
+		}
+		if available {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, true, nil
+}
+
+// isHeightAvailable reports whether the node is expected to serve state at height.
+func (d *Indexer) isHeightAvailable(height uint64) bool {
+	return height >= d.minAvailableHeight.Load()
+}
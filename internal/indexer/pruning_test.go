@@ -0,0 +1,61 @@
+package indexer
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+type prunedRPC struct {
+	ExpectedRPC
+	minHeight int64
+	calls     int
+	err       error
+}
+
+func (r *prunedRPC) HasStateAt(height int64) (bool, error) {
+	r.calls++
+	return height >= r.minHeight, r.err
+}
+
+func TestIndexer_probeMinAvailableHeight(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   uint64
+		blocks    uint64
+		prev      uint64
+		rpc       *prunedRPC
+		want      uint64
+		wantErr   bool
+		wantCalls int
+	}{
+		{"archive", 1000, 100, 0, &prunedRPC{minHeight: 1}, 900, false, 1},
+		{"pruned", 1000, 100, 0, &prunedRPC{minHeight: 950}, 950, false, 9},
+		{"nothing available", 1000, 100, 0, &prunedRPC{minHeight: 2000}, 0, false, 2},
+		{"nothing available keeps previous", 1000, 100, 950, &prunedRPC{minHeight: 2000}, 950, false, 2},
+		{"history available again", 1000, 100, 950, &prunedRPC{minHeight: 1}, 900, false, 1},
+		{"short chain", 50, 100, 0, &prunedRPC{minHeight: 10}, 10, false, 8},
+		{"error", 1000, 100, 0, &prunedRPC{err: errors.New("unavailable")}, 0, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Indexer{
+				logger: slog.Default(),
+				rpc:    tt.rpc,
+			}
+			d.currentBlockHeight.Store(tt.current)
+			d.minAvailableHeight.Store(tt.prev)
+
+			err := d.probeMinAvailableHeight(tt.blocks)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Indexer.probeMinAvailableHeight() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := d.minAvailableHeight.Load(); got != tt.want {
+				t.Errorf("Indexer.probeMinAvailableHeight() = %d, want %d", got, tt.want)
+			}
+			if tt.rpc.calls != tt.wantCalls {
+				t.Errorf("Indexer.probeMinAvailableHeight() calls = %d, want %d", tt.rpc.calls, tt.wantCalls)
+			}
+		})
+	}
+}
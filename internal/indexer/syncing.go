@@ -20,6 +20,9 @@ func (d *Indexer) handleSyncing(blocks uint64) error {
 			d.logger.Info("indexer.handleSyncing: c.Context Done")
 			return nil
 		case height := <-d.syncHeights:
+			if !d.isHeightAvailable(height) {
+				continue
+			}
 			d.logger.Info("SYNC", "height", height, "current_height", d.currentBlockHeight.Load(), "queue_heights", len(d.syncHeights))
 			err := d.syncHeight(height)
 			if err != nil {
@@ -34,6 +37,9 @@ func (d *Indexer) handleSyncing(blocks uint64) error {
 func (d *Indexer) monitorHeights(blocks uint64) error {
 	ticker := time.NewTicker(time.Minute)
 	for {
+		if err := d.probeMinAvailableHeight(blocks); err != nil {
+			d.logger.Warn("SYNC: Failed probing node for available heights", "err", err)
+		}
 		err := d.queueMissingHeights(blocks)
 		if err != nil {
 			return err
@@ -58,6 +64,9 @@ func (d *Indexer) queueMissingHeights(blocks uint64) error {
 	}
 	heightEnd := d.currentBlockHeight.Load()
 	heightStart := heightEnd - blocks
+	if minHeight := d.minAvailableHeight.Load(); minHeight > heightStart {
+		heightStart = minHeight
+	}
 
 	for i := heightStart; i < heightEnd; i++ {
 		// NOTE: Here we assume that if one pool is missing from the height, then all pools are missing most likely.
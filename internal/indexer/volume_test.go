@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"fmt"
+	"log/slog"
 	"math"
 	"math/big"
 	"reflect"
@@ -26,6 +27,7 @@ func TestIndexer_fetchVolumeValuesPerBlockRange(t *testing.T) {
 			10, 12, 13,
 			func() *Indexer {
 				ret := &Indexer{
+					logger:  slog.Default(),
 					verbose: true,
 					prices: PriceMap{
 						prices: make(map[string][]repository.TokenPrice),
@@ -260,6 +262,7 @@ func TestIndexer_calculateVolumeValueAt(t *testing.T) {
 			"normal",
 			func() *Indexer {
 				ret := &Indexer{
+					logger: slog.Default(),
 					prices: PriceMap{
 						prices: make(map[string][]repository.TokenPrice),
 					},
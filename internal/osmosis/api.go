@@ -0,0 +1,451 @@
+package osmosis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+)
+
+const (
+	// APIPrefix is the path of the HTTP API.
+	APIPrefix = "/api/v1/"
+
+	apiDefaultLimit = 100
+	apiMaxLimit     = 1000
+	// apiDefaultPriceRange is the price history returned when the range is not set.
+	apiDefaultPriceRange = 24 * time.Hour
+)
+
+// Output formats of the HTTP API.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// APIPage is a page of a list returned by the HTTP API. The total count is also sent in the X-Total-Count header.
+type APIPage[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+type APIPool struct {
+	Height    uint64           `json:"height"`
+	PoolId    uint64           `json:"pool_id"`
+	Liquidity cosmotypes.Coins `json:"liquidity"`
+	Volume    cosmotypes.Coins `json:"volume"`
+}
+
+type APIPrice struct {
+	Denom       string    `json:"denom"`
+	Base        string    `json:"base"`
+	Value       float64   `json:"value"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+type APIDenom struct {
+	Denom     string `json:"denom"`
+	Path      string `json:"path"`
+	BaseDenom string `json:"base_denom"`
+}
+
+var (
+	apiPoolHeader  = []string{"height", "pool_id", "liquidity", "volume"}
+	apiPriceHeader = []string{"denom", "base", "value", "last_updated"}
+	apiDenomHeader = []string{"denom", "path", "base_denom"}
+)
+
+func (p APIPool) record() []string {
+	return []string{strconv.FormatUint(p.Height, 10), strconv.FormatUint(p.PoolId, 10), p.Liquidity.String(), p.Volume.String()}
+}
+
+func (p APIPrice) record() []string {
+	return []string{p.Denom, p.Base, strconv.FormatFloat(p.Value, 'f', -1, 64), p.LastUpdated.UTC().Format(time.RFC3339Nano)}
+}
+
+func (d APIDenom) record() []string {
+	return []string{d.Denom, d.Path, d.BaseDenom}
+}
+
+func newAPIPool(pool repository.Pool) APIPool {
+	return APIPool{
+		Height:    pool.Height,
+		PoolId:    pool.PoolId,
+		Liquidity: pool.Liquidity,
+		Volume:    pool.Volume,
+	}
+}
+
+func newAPIPrice(price repository.TokenPrice) APIPrice {
+	return APIPrice{
+		Denom:       price.Name,
+		Base:        price.Base,
+		Value:       price.Value,
+		LastUpdated: price.LastUpdated,
+	}
+}
+
+func newAPIDenom(trace ibctypes.DenomTrace) APIDenom {
+	return APIDenom{
+		Denom:     trace.IBCDenom(),
+		Path:      trace.Path,
+		BaseDenom: trace.BaseDenom,
+	}
+}
+
+// serveHTTP serves Prometheus metrics and the HTTP API until the publisher is closed.
+// They share the listener when their addresses are the same.
+func (p *Publisher) serveHTTP() {
+	muxes := make(map[string]*http.ServeMux)
+	mux := func(addr string) *http.ServeMux {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+	if addr := p.MetricsURL(); addr != "" {
+		mux(addr).Handle("/metrics", promhttp.Handler())
+	}
+	if addr := p.APIAddr(); addr != "" {
+		mux(addr).Handle(APIPrefix, newAPIHandler(p.db, p.Logger))
+		p.Logger.Info("Serving HTTP API", "addr", addr)
+	}
+
+	for addr, handler := range muxes {
+		server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+		p.Group.Go(
+			func() error {
+				go func() {
+					<-p.Context.Done()
+					server.Close()
+				}()
+				if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					p.Logger.Error("HTTP server failed", "addr", server.Addr, "err", err)
+				}
+				return nil
+			},
+		)
+	}
+}
+
+// apiHandler serves the repository over HTTP:
+//
+//	GET /api/v1/pools/{id}?from_height=&to_height=  pool history, to_height defaults to the latest stored height
+//	GET /api/v1/pools/{id}/latest                   latest stored pool
+//	GET /api/v1/prices/{denom}?from=&to=            price history, the last 24 hours by default
+//	GET /api/v1/prices/{denom}/nearest?timestamp=   stored price nearest to timestamp, now by default
+//	GET /api/v1/denoms                              all stored IBC denom traces
+//	GET /api/v1/denoms/ibc/{hash}                   IBC denom trace
+//
+// Lists accept offset and limit. Output is JSON, or CSV with format=csv or "Accept: text/csv".
+// Errors are returned as {"error": {"code": "...", "message": "..."}} the same as in queries.
+type apiHandler struct {
+	db     repository.Repository
+	logger *slog.Logger
+}
+
+func newAPIHandler(db repository.Repository, logger *slog.Logger) http.Handler {
+	return &apiHandler{db: db, logger: logger}
+}
+
+func (h *apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		h.writeError(w, http.StatusMethodNotAllowed, queryError(QueryErrBadRequest, "method %s is not allowed", r.Method))
+		return
+	}
+
+	format, err := apiFormat(r)
+	if err != nil {
+		h.writeError(w, 0, err)
+		return
+	}
+
+	resource, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, APIPrefix), "/")
+	switch resource {
+	case "pools":
+		id, latest := strings.CutSuffix(rest, "/latest")
+		poolId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			err = queryError(QueryErrBadRequest, "bad pool id %q", id)
+		} else if latest {
+			err = h.latestPool(w, format, poolId)
+		} else {
+			err = h.pools(w, r, format, poolId)
+		}
+		h.writeError(w, 0, err)
+	case "prices":
+		denom, nearest := strings.CutSuffix(rest, "/nearest")
+		if denom == "" {
+			err = queryError(QueryErrBadRequest, "denom is required")
+		} else if nearest {
+			err = h.nearestPrice(w, r, format, denom)
+		} else {
+			err = h.prices(w, r, format, denom)
+		}
+		h.writeError(w, 0, err)
+	case "denoms":
+		if rest == "" {
+			err = h.denoms(w, r, format)
+		} else {
+			err = h.denom(w, format, rest)
+		}
+		h.writeError(w, 0, err)
+	default:
+		h.writeError(w, 0, queryError(QueryErrNotFound, "unknown path %s", r.URL.Path))
+	}
+}
+
+func (h *apiHandler) pools(w http.ResponseWriter, r *http.Request, format string, poolId uint64) error {
+	latest, found := h.db.LatestPool(poolId)
+	if !found {
+		return queryError(QueryErrNotFound, "pool %d not found", poolId)
+	}
+	minHeight, err := apiUint(r, "from_height", 0)
+	if err != nil {
+		return err
+	}
+	maxHeight, err := apiUint(r, "to_height", latest.Height)
+	if err != nil {
+		return err
+	}
+	if minHeight > maxHeight {
+		return queryError(QueryErrBadRequest, "from_height is after to_height")
+	}
+	offset, limit, err := apiPage(r)
+	if err != nil {
+		return err
+	}
+
+	pools, total, err := h.db.PoolsPage(minHeight, maxHeight, poolId, offset, limit)
+	if err != nil {
+		return err
+	}
+	items := make([]APIPool, len(pools))
+	for i, pool := range pools {
+		items[i] = newAPIPool(pool)
+	}
+	return writePage(w, format, apiPoolHeader, items, total, offset, limit)
+}
+
+func (h *apiHandler) latestPool(w http.ResponseWriter, format string, poolId uint64) error {
+	pool, found := h.db.LatestPool(poolId)
+	if !found {
+		return queryError(QueryErrNotFound, "pool %d not found", poolId)
+	}
+	return writeItem(w, format, apiPoolHeader, newAPIPool(pool))
+}
+
+func (h *apiHandler) prices(w http.ResponseWriter, r *http.Request, format string, denom string) error {
+	to, err := apiTime(r, "to", time.Now())
+	if err != nil {
+		return err
+	}
+	from, err := apiTime(r, "from", to.Add(-apiDefaultPriceRange))
+	if err != nil {
+		return err
+	}
+	if from.After(to) {
+		return queryError(QueryErrBadRequest, "from is after to")
+	}
+	offset, limit, err := apiPage(r)
+	if err != nil {
+		return err
+	}
+
+	prices, total, err := h.db.TokenPricesPage(from, to, denom, offset, limit)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		if _, found := h.db.LatestTokenPrice(denom); !found {
+			return queryError(QueryErrNotFound, "no prices of %s", denom)
+		}
+	}
+	items := make([]APIPrice, len(prices))
+	for i, price := range prices {
+		items[i] = newAPIPrice(price)
+	}
+	return writePage(w, format, apiPriceHeader, items, total, offset, limit)
+}
+
+func (h *apiHandler) nearestPrice(w http.ResponseWriter, r *http.Request, format string, denom string) error {
+	timestamp, err := apiTime(r, "timestamp", time.Now())
+	if err != nil {
+		return err
+	}
+	prices, found := h.db.NearestTokenPrice(timestamp, denom)
+	if !found || len(prices) == 0 {
+		return queryError(QueryErrNotFound, "no prices of %s", denom)
+	}
+	return writeItem(w, format, apiPriceHeader, newAPIPrice(nearestTokenPrice(timestamp, prices)))
+}
+
+func (h *apiHandler) denoms(w http.ResponseWriter, r *http.Request, format string) error {
+	offset, limit, err := apiPage(r)
+	if err != nil {
+		return err
+	}
+
+	traces := h.db.IBCDenomAll()
+	start := min(offset, len(traces))
+	end := start + min(limit, len(traces)-start)
+	items := make([]APIDenom, end-start)
+	for i, trace := range traces[start:end] {
+		items[i] = newAPIDenom(trace)
+	}
+	return writePage(w, format, apiDenomHeader, items, len(traces), offset, limit)
+}
+
+func (h *apiHandler) denom(w http.ResponseWriter, format string, denom string) error {
+	if !strings.HasPrefix(denom, "ibc/") {
+		return queryError(QueryErrBadRequest, "denom must be an IBC denom ibc/{hash}")
+	}
+	denom = "ibc/" + strings.ToUpper(strings.TrimPrefix(denom, "ibc/"))
+	trace, found := h.db.IBCDenom(denom)
+	if !found {
+		return queryError(QueryErrNotFound, "unknown denom %s", denom)
+	}
+	return writeItem(w, format, apiDenomHeader, newAPIDenom(trace))
+}
+
+// writeError writes err with status, or with the status of its code when status is zero. Nil err is ignored.
+func (h *apiHandler) writeError(w http.ResponseWriter, status int, err error) {
+	if err == nil {
+		return
+	}
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		h.logger.Error("HTTP API failed", "err", err)
+		queryErr = queryError(QueryErrInternal, "%s", err)
+	}
+	if status == 0 {
+		switch queryErr.Code {
+		case QueryErrBadRequest:
+			status = http.StatusBadRequest
+		case QueryErrNotFound:
+			status = http.StatusNotFound
+		default:
+			status = http.StatusInternalServerError
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(QueryResponse{Error: queryErr})
+}
+
+func apiFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case FormatJSON, FormatCSV:
+		return format, nil
+	case "":
+		if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+			return FormatCSV, nil
+		}
+		return FormatJSON, nil
+	default:
+		return "", queryError(QueryErrBadRequest, "unknown format %q", format)
+	}
+}
+
+func apiUint(r *http.Request, name string, value uint64) (uint64, error) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return value, nil
+	}
+	value, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return 0, queryError(QueryErrBadRequest, "bad %s %q", name, param)
+	}
+	return value, nil
+}
+
+// apiTime parses a RFC 3339 time or Unix seconds.
+func apiTime(r *http.Request, name string, value time.Time) (time.Time, error) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return value, nil
+	}
+	if seconds, err := strconv.ParseInt(param, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	value, err := time.Parse(time.RFC3339, param)
+	if err != nil {
+		return time.Time{}, queryError(QueryErrBadRequest, "bad %s %q", name, param)
+	}
+	return value, nil
+}
+
+// apiPage parses offset and limit of a list.
+func apiPage(r *http.Request) (int, int, error) {
+	offset, err := apiUint(r, "offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	if offset > math.MaxInt32 {
+		return 0, 0, queryError(QueryErrBadRequest, "offset must be at most %d", math.MaxInt32)
+	}
+	limit, err := apiUint(r, "limit", apiDefaultLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	if limit == 0 || limit > apiMaxLimit {
+		return 0, 0, queryError(QueryErrBadRequest, "limit must be 1 to %d", apiMaxLimit)
+	}
+	return int(offset), int(limit), nil
+}
+
+// writePage writes the items found at offset of the total. Like writeItem, it only fails before anything is written.
+func writePage[T interface{ record() []string }](w http.ResponseWriter, format string, header []string, items []T, total, offset, limit int) error {
+	page := APIPage[T]{
+		Items:  items,
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	if format == FormatCSV {
+		return writeCSV(w, header, page.Items)
+	}
+	return writeJSON(w, page)
+}
+
+func writeItem[T interface{ record() []string }](w http.ResponseWriter, format string, header []string, item T) error {
+	if format == FormatCSV {
+		return writeCSV(w, header, []T{item})
+	}
+	return writeJSON(w, item)
+}
+
+func writeJSON(w http.ResponseWriter, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+	return nil
+}
+
+func writeCSV[T interface{ record() []string }](w http.ResponseWriter, header []string, items []T) error {
+	w.Header().Set("Content-Type", "text/csv")
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	for _, item := range items {
+		cw.Write(item.record())
+	}
+	cw.Flush()
+	return nil
+}
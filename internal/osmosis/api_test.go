@@ -0,0 +1,160 @@
+package osmosis
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+)
+
+type apiRepository struct {
+	repository.Repository
+	pools  []repository.Pool
+	prices []repository.TokenPrice
+	traces []ibctypes.DenomTrace
+}
+
+func (r apiRepository) LatestPool(id uint64) (repository.Pool, bool) {
+	for i := len(r.pools) - 1; i >= 0; i-- {
+		if r.pools[i].PoolId == id {
+			return r.pools[i], true
+		}
+	}
+	return repository.Pool{}, false
+}
+
+func (r apiRepository) PoolsRange(min, max, poolId uint64) ([]repository.Pool, error) {
+	var pools []repository.Pool
+	for _, pool := range r.pools {
+		if pool.PoolId == poolId && pool.Height >= min && pool.Height <= max {
+			pools = append(pools, pool)
+		}
+	}
+	return pools, nil
+}
+
+func (r apiRepository) PoolsPage(min, max, poolId uint64, offset, limit int) ([]repository.Pool, int, error) {
+	pools, _ := r.PoolsRange(min, max, poolId)
+	return page(pools, offset, limit), len(pools), nil
+}
+
+func (r apiRepository) TokenPricesRange(min, max time.Time, denom string) ([]repository.TokenPrice, error) {
+	var prices []repository.TokenPrice
+	for _, price := range r.prices {
+		if price.Name == denom && !price.LastUpdated.Before(min) && !price.LastUpdated.After(max) {
+			prices = append(prices, price)
+		}
+	}
+	return prices, nil
+}
+
+func (r apiRepository) TokenPricesPage(min, max time.Time, denom string, offset, limit int) ([]repository.TokenPrice, int, error) {
+	prices, _ := r.TokenPricesRange(min, max, denom)
+	return page(prices, offset, limit), len(prices), nil
+}
+
+func page[T any](items []T, offset, limit int) []T {
+	start := min(offset, len(items))
+	return items[start : start+min(limit, len(items)-start)]
+}
+
+func (r apiRepository) LatestTokenPrice(denom string) (repository.TokenPrice, bool) {
+	for i := len(r.prices) - 1; i >= 0; i-- {
+		if r.prices[i].Name == denom {
+			return r.prices[i], true
+		}
+	}
+	return repository.TokenPrice{}, false
+}
+
+func (r apiRepository) NearestTokenPrice(timestamp time.Time, denom string) ([]repository.TokenPrice, bool) {
+	prices, _ := r.TokenPricesRange(time.Time{}, timestamp.Add(time.Hour), denom)
+	return prices, len(prices) > 0
+}
+
+func (r apiRepository) IBCDenom(ibc string) (ibctypes.DenomTrace, bool) {
+	for _, trace := range r.traces {
+		if trace.IBCDenom() == ibc {
+			return trace, true
+		}
+	}
+	return ibctypes.DenomTrace{}, false
+}
+
+func (r apiRepository) IBCDenomAll() []ibctypes.DenomTrace {
+	return r.traces
+}
+
+func TestAPIHandler(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	atom := ibctypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"}
+	handler := newAPIHandler(apiRepository{
+		pools: []repository.Pool{
+			{Height: 10, PoolId: 1, Liquidity: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("uosmo", 5))},
+			{Height: 11, PoolId: 1, Liquidity: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("uosmo", 6))},
+			{Height: 11, PoolId: 2},
+			{Height: 12, PoolId: 1, Liquidity: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("uosmo", 7))},
+		},
+		prices: []repository.TokenPrice{
+			{Name: "uosmo", Base: "USD", Value: 0.5, LastUpdated: now.Add(-2 * time.Hour)},
+			{Name: "uosmo", Base: "USD", Value: 0.6, LastUpdated: now.Add(-time.Minute)},
+		},
+		traces: []ibctypes.DenomTrace{atom},
+	}, slog.Default())
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"pools", "/api/v1/pools/1?from_height=11&limit=1", http.StatusOK, `{"items":[{"height":11,"pool_id":1,"liquidity":[{"denom":"uosmo","amount":"6"}],"volume":[]}],"total":2,"offset":0,"limit":1}` + "\n"},
+		{"pools page", "/api/v1/pools/1?offset=5", http.StatusOK, `{"items":[],"total":3,"offset":5,"limit":100}` + "\n"},
+		{"pools csv", "/api/v1/pools/1?to_height=10&format=csv", http.StatusOK, "height,pool_id,liquidity,volume\n10,1,5uosmo,\n"},
+		{"unknown pool", "/api/v1/pools/3", http.StatusNotFound, ""},
+		{"bad pool", "/api/v1/pools/osmo", http.StatusBadRequest, ""},
+		{"bad range", "/api/v1/pools/1?from_height=12&to_height=11", http.StatusBadRequest, ""},
+		{"bad limit", "/api/v1/pools/1?limit=0", http.StatusBadRequest, ""},
+		{"latest pool", "/api/v1/pools/1/latest?format=csv", http.StatusOK, "height,pool_id,liquidity,volume\n12,1,7uosmo,\n"},
+		{"prices", "/api/v1/prices/uosmo?from=1699990000&to=2023-11-14T22:13:20Z&format=csv", http.StatusOK, "denom,base,value,last_updated\nuosmo,USD,0.5,2023-11-14T20:13:20Z\nuosmo,USD,0.6,2023-11-14T22:12:20Z\n"},
+		{"prices page", "/api/v1/prices/uosmo?from=1699990000&offset=1&limit=1", http.StatusOK, `{"items":[{"denom":"uosmo","base":"USD","value":0.6,"last_updated":"2023-11-14T22:12:20Z"}],"total":2,"offset":1,"limit":1}` + "\n"},
+		{"bad offset", "/api/v1/denoms?offset=99999999999", http.StatusBadRequest, ""},
+		{"no prices", "/api/v1/prices/uatom", http.StatusNotFound, ""},
+		{"nearest price", "/api/v1/prices/uosmo/nearest?timestamp=1700000000", http.StatusOK, `{"denom":"uosmo","base":"USD","value":0.6,"last_updated":"2023-11-14T22:12:20Z"}` + "\n"},
+		{"denoms", "/api/v1/denoms?format=csv", http.StatusOK, "denom,path,base_denom\n" + atom.IBCDenom() + ",transfer/channel-0,uatom\n"},
+		{"denom", "/api/v1/denoms/" + atom.IBCDenom(), http.StatusOK, `{"denom":"` + atom.IBCDenom() + `","path":"transfer/channel-0","base_denom":"uatom"}` + "\n"},
+		{"unknown denom", "/api/v1/denoms/ibc/ABC", http.StatusNotFound, ""},
+		{"unknown path", "/api/v1/blocks", http.StatusNotFound, ""},
+		{"bad format", "/api/v1/denoms?format=xml", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body, tt.wantBody)
+			}
+			if tt.wantStatus != http.StatusOK {
+				var response QueryResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Error == nil {
+					t.Errorf("bad error body %s: %v", w.Body, err)
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/denoms", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
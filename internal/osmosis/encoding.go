@@ -0,0 +1,236 @@
+package osmosis
+
+import (
+	"fmt"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	gogoproto "github.com/cosmos/gogoproto/proto"
+	"github.com/synternet/data-layer-sdk/pkg/codec"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/types"
+	"github.com/synternet/osmosis-publisher/pkg/types/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+)
+
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+// NewCodec returns the codec of published messages for the encoding.
+func NewCodec(encoding string) (options.Codec, error) {
+	switch encoding {
+	case "", EncodingJSON:
+		return &JSONCodec{}, nil
+	case EncodingProtobuf:
+		return &ProtobufCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q, expected %q or %q", encoding, EncodingJSON, EncodingProtobuf)
+	}
+}
+
+// JSONCodec encodes messages of pkg/types/pb with the protobuf JSON mapping and the field names of the schemas.
+// Other messages, e.g. of pkg/types or telemetry, are encoded with encoding/json.
+type JSONCodec struct {
+	codec.JsonCodec
+}
+
+func (c *JSONCodec) Encode(buf []byte, msg proto.Message) ([]byte, error) {
+	if !isPublisherSchema(msg) {
+		return c.JsonCodec.Encode(buf, msg)
+	}
+	return protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(msg)
+}
+
+func (c *JSONCodec) Decode(buf []byte, msg proto.Message) error {
+	if !isPublisherSchema(msg) {
+		return c.JsonCodec.Decode(buf, msg)
+	}
+	return protojson.Unmarshal(buf, msg)
+}
+
+func isPublisherSchema(msg proto.Message) bool {
+	m := msg.ProtoReflect()
+	return m != nil && m.Descriptor().ParentFile() == pb.File_osmosis_publisher_types_proto
+}
+
+// ProtobufCodec encodes published messages using the schemas in pkg/types/pb.
+// Messages of pkg/types are converted to their protobuf counterparts, other protobuf messages are encoded as is.
+type ProtobufCodec struct{}
+
+func (c *ProtobufCodec) Encode(buf []byte, msg proto.Message) ([]byte, error) {
+	m, err := toProtobuf(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{Deterministic: true}.MarshalAppend(buf, m)
+}
+
+func (c *ProtobufCodec) Decode(buf []byte, msg proto.Message) error {
+	return proto.Unmarshal(buf, msg)
+}
+
+func toProtobuf(msg proto.Message) (proto.Message, error) {
+	switch m := msg.(type) {
+	case *types.Block:
+		return blockToProtobuf(m)
+	case *types.Transaction:
+		return transactionToProtobuf(m)
+	case *types.Mempool:
+		txs := make([]*pb.Transaction, 0, len(m.Transactions))
+		for _, tx := range m.Transactions {
+			ptx, err := transactionToProtobuf(tx)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, ptx)
+		}
+		return &pb.Mempool{Nonce: m.Nonce, Txs: txs}, nil
+	case *types.PoolOfInterest:
+		return &pb.PoolOfInterest{
+			Nonce:        m.Nonce,
+			BlockHeight:  m.BlockHeight,
+			AvgBlockTime: m.AvgBlockTime,
+			BlockHash:    m.BlockHash,
+			Pools:        poolStatusesToProtobuf(m.Pools),
+			Metadata:     metadataToProtobuf(m.Metadata),
+		}, nil
+	case types.Pools:
+		return poolsToProtobuf(&m)
+	case *types.Pools:
+		return poolsToProtobuf(m)
+	}
+
+	if msg.ProtoReflect() == nil {
+		return nil, fmt.Errorf("%T has no protobuf representation", msg)
+	}
+	return msg, nil
+}
+
+func blockToProtobuf(m *types.Block) (*pb.Block, error) {
+	blockProto, ok := m.Block.(*cmtproto.Block)
+	if !ok {
+		return nil, fmt.Errorf("unexpected block type %T", m.Block)
+	}
+	raw, err := blockProto.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed marshalling block: %w", err)
+	}
+	block, err := tmtypes.BlockFromProto(blockProto)
+	if err != nil {
+		return nil, fmt.Errorf("failed converting block: %w", err)
+	}
+
+	return &pb.Block{
+		Nonce:   m.Nonce,
+		ChainId: block.ChainID,
+		Height:  block.Height,
+		Hash:    block.Hash().String(),
+		Time:    timestamppb.New(block.Time),
+		Block:   raw,
+	}, nil
+}
+
+func transactionToProtobuf(m *types.Transaction) (*pb.Transaction, error) {
+	tx := &pb.Transaction{
+		Nonce:        m.Nonce,
+		TxId:         m.TxID,
+		Code:         m.Code,
+		MessageTypes: extractTxMessageNames(m),
+		Raw:          m.RawBytes,
+		Metadata:     metadataToProtobuf(m.Metadata),
+	}
+
+	if result, ok := m.TxResult.(*abci.TxResult); ok && result != nil {
+		raw, err := result.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling tx result: %w", err)
+		}
+		tx.Height = result.Height
+		tx.TxResult = raw
+	}
+	return tx, nil
+}
+
+func poolsToProtobuf(m *types.Pools) (*pb.Pools, error) {
+	pools := make([]*anypb.Any, 0, len(m.Pools))
+	for _, pool := range m.Pools {
+		poolProto, ok := pool.(gogoproto.Message)
+		if !ok {
+			return nil, fmt.Errorf("unexpected pool type %T", pool)
+		}
+		packed, err := codectypes.NewAnyWithValue(poolProto)
+		if err != nil {
+			return nil, fmt.Errorf("failed packing pool: %w", err)
+		}
+		pools = append(pools, &anypb.Any{TypeUrl: packed.TypeUrl, Value: packed.Value})
+	}
+
+	var events map[string]*pb.EventValues
+	if ev, ok := m.Events.(map[string][]string); ok {
+		events = make(map[string]*pb.EventValues, len(ev))
+		for k, v := range ev {
+			events[k] = &pb.EventValues{Values: v}
+		}
+	}
+
+	return &pb.Pools{
+		Nonce:        m.Nonce,
+		BlockHeight:  m.BlockHeight,
+		AvgBlockTime: m.AvgBlockTime,
+		BlockHash:    m.BlockHash,
+		Pools:        pools,
+		PoolsStatus:  poolStatusesToProtobuf(m.PoolStatus),
+		Events:       events,
+		Metadata:     metadataToProtobuf(m.Metadata),
+	}, nil
+}
+
+func poolStatusesToProtobuf(statuses []types.PoolStatus) []*pb.PoolStatus {
+	res := make([]*pb.PoolStatus, len(statuses))
+	for i, s := range statuses {
+		volumes := make([]*pb.PoolStatusVolumeAt, len(s.Volumes))
+		for j, v := range s.Volumes {
+			volumes[j] = &pb.PoolStatusVolumeAt{
+				BlockHeight:       v.BlockHeight,
+				Volume:            coinsToProtobuf(v.Volume),
+				VolumeUsd:         v.VolumeUSD,
+				RelativeVolumeUsd: v.RelativeVolumeUSD,
+			}
+		}
+		res[i] = &pb.PoolStatus{
+			PoolId:         s.PoolId,
+			TotalLiquidity: coinsToProtobuf(s.TotalLiquidity),
+			TotalVolume:    volumes,
+		}
+	}
+	return res
+}
+
+func coinsToProtobuf(coins cosmotypes.Coins) []*pb.Coin {
+	res := make([]*pb.Coin, len(coins))
+	for i, c := range coins {
+		res[i] = &pb.Coin{Denom: c.Denom, Amount: c.Amount.String()}
+	}
+	return res
+}
+
+func metadataToProtobuf(metadata any) map[string]*pb.DenomTrace {
+	ibcMap, ok := metadata.(IBCDenomTrace)
+	if !ok || len(ibcMap) == 0 {
+		return nil
+	}
+	res := make(map[string]*pb.DenomTrace, len(ibcMap))
+	for denom, trace := range ibcMap {
+		res[denom] = &pb.DenomTrace{Path: trace.Path, BaseDenom: trace.BaseDenom}
+	}
+	return res
+}
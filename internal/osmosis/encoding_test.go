@@ -0,0 +1,161 @@
+package osmosis
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/osmosis-publisher/pkg/types"
+	"github.com/synternet/osmosis-publisher/pkg/types/pb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestNewCodec(t *testing.T) {
+	for _, encoding := range []string{"", EncodingJSON, EncodingProtobuf} {
+		if _, err := NewCodec(encoding); err != nil {
+			t.Errorf("NewCodec(%q) failed: %v", encoding, err)
+		}
+	}
+	if _, err := NewCodec("xml"); err == nil {
+		t.Errorf("NewCodec must fail for unknown encodings")
+	}
+}
+
+func TestProtobufCodec_Block(t *testing.T) {
+	block := tmtypes.MakeBlock(100, []tmtypes.Tx{tmtypes.Tx("tx")}, &tmtypes.Commit{}, nil)
+	block.ChainID = "osmosis-1"
+	block.ProposerAddress = make([]byte, 20)
+	blockProto, err := block.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ProtobufCodec{}
+	buf, err := c.Encode(nil, &types.Block{Nonce: "1", Block: blockProto})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var got pb.Block
+	if err := c.Decode(buf, &got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if got.Nonce != "1" || got.ChainId != "osmosis-1" || got.Height != 100 || got.Hash != block.Hash().String() || len(got.Block) == 0 {
+		t.Errorf("unexpected block %v", &got)
+	}
+}
+
+func TestProtobufCodec_Transaction(t *testing.T) {
+	result := &abci.TxResult{Height: 10, Tx: []byte("tx")}
+	c := &ProtobufCodec{}
+	buf, err := c.Encode(nil, &types.Mempool{
+		Nonce: "1",
+		Transactions: []*types.Transaction{
+			{TxID: "A", RawBytes: []byte("tx"), TxResult: result, Metadata: IBCDenomTrace{"ibc/A": ibctypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"}}},
+			{TxID: "B", TxResult: (*abci.TxResult)(nil)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var got pb.Mempool
+	if err := c.Decode(buf, &got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(got.Txs) != 2 {
+		t.Fatalf("got %d txs, want 2", len(got.Txs))
+	}
+	if tx := got.Txs[0]; tx.TxId != "A" || tx.Height != 10 || string(tx.Raw) != "tx" || len(tx.TxResult) == 0 || tx.Metadata["ibc/A"].GetBaseDenom() != "uatom" {
+		t.Errorf("unexpected tx %v", tx)
+	}
+	if tx := got.Txs[1]; tx.TxId != "B" || tx.TxResult != nil {
+		t.Errorf("unexpected tx %v", tx)
+	}
+}
+
+func TestProtobufCodec_Pools(t *testing.T) {
+	c := &ProtobufCodec{}
+	buf, err := c.Encode(nil, types.Pools{
+		Nonce:       "1",
+		BlockHeight: 10,
+		PoolStatus: []types.PoolStatus{{
+			PoolId:         1,
+			TotalLiquidity: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("uosmo", 5)),
+			Volumes:        []types.PoolStatusVolumeAt{{BlockHeight: 9, VolumeUSD: []float64{1.5}}},
+		}},
+		Events: map[string][]string{"token_swapped.pool_id": {"1"}},
+	})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var got pb.Pools
+	if err := c.Decode(buf, &got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if got.BlockHeight != 10 || len(got.PoolsStatus) != 1 || got.Events["token_swapped.pool_id"].GetValues()[0] != "1" {
+		t.Fatalf("unexpected pools %v", &got)
+	}
+	status := got.PoolsStatus[0]
+	if status.TotalLiquidity[0].Amount != "5" || status.TotalVolume[0].VolumeUsd[0] != 1.5 {
+		t.Errorf("unexpected pool status %v", status)
+	}
+}
+
+func TestCodecs_Swap(t *testing.T) {
+	swap := &pb.Swap{
+		Nonce:      "1",
+		Height:     10,
+		Time:       timestamppb.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+		TxHash:     "A",
+		PoolId:     1,
+		TokenIn:    &pb.Coin{Denom: "uosmo", Amount: "5"},
+		TokenOut:   &pb.Coin{Denom: "ibc/B", Amount: "2"},
+		TokenInUsd: 2.5,
+		Metadata:   metadataToProtobuf(IBCDenomTrace{"ibc/B": ibctypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"}}),
+	}
+	for _, encoding := range []string{EncodingJSON, EncodingProtobuf} {
+		c, _ := NewCodec(encoding)
+		buf, err := c.Encode(nil, swap)
+		if err != nil {
+			t.Fatalf("%s Encode failed: %v", encoding, err)
+		}
+		var got pb.Swap
+		if err := c.Decode(buf, &got); err != nil {
+			t.Fatalf("%s Decode failed: %v", encoding, err)
+		}
+		if !proto.Equal(&got, swap) {
+			t.Errorf("%s decoded swap %v, want %v", encoding, &got, swap)
+		}
+	}
+}
+
+func TestJSONCodec(t *testing.T) {
+	c := &JSONCodec{}
+	buf, err := c.Encode(nil, &pb.Swap{TxHash: "A", Time: timestamppb.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatalf("bad swap %s: %v", buf, err)
+	}
+	if got["tx_hash"] != "A" || got["time"] != "2024-03-01T12:00:00Z" || got["token_in_usd"] != 0.0 {
+		t.Errorf("swap = %s, want schema field names, RFC 3339 time and unpopulated fields", buf)
+	}
+
+	buf, err = c.Encode(nil, &types.Block{Nonce: "1"})
+	if err != nil || string(buf) != `{"nonce":"1","block":null}` {
+		t.Errorf("Encode(Block) = %s, %v", buf, err)
+	}
+}
+
+func TestProtobufCodec_Unsupported(t *testing.T) {
+	c := &ProtobufCodec{}
+	if _, err := c.Encode(nil, &types.Block{Block: "not a block"}); err == nil {
+		t.Errorf("Encode must fail for unexpected block content")
+	}
+}
@@ -0,0 +1,50 @@
+package osmosis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Message ids identify the content of a message rather than the publishing process, so that
+// JetStream drops messages republished after a restart, a failover or a backfill.
+
+func blockMsgID(chainId string, height int64) string {
+	return fmt.Sprintf("block.%s.%d", chainId, height)
+}
+
+func txMsgID(hash string) string {
+	return "tx." + hash
+}
+
+// swapMsgID identifies the swap at index of the transaction.
+func swapMsgID(hash string, index uint32) string {
+	return fmt.Sprintf("swap.%s.%d", hash, index)
+}
+
+// poolsMsgID identifies pool messages of subject at height. Pool ids are sorted, so that the order does not matter.
+func poolsMsgID(subject string, height int64, poolIds []uint64) string {
+	ids := slices.Clone(poolIds)
+	slices.Sort(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(id, 10)
+	}
+	return fmt.Sprintf("%s.%d.%s", subject, height, strings.Join(parts, ","))
+}
+
+// poolMsgID identifies messages of a single pool at height. The subject includes the pool id, so that the id differs from
+// the one of the aggregated message of a single pool, e.g. "volume.pool.1.100" and "volume.pool.100.1".
+func poolMsgID(subject string, poolId uint64, height int64) string {
+	return fmt.Sprintf("%s.%d.%d", subject, poolId, height)
+}
+
+// revisedMsgID identifies a revision of a message, which replaces the earlier ones with more content.
+func revisedMsgID(id string, revision int) string {
+	if revision == 0 {
+		return id
+	}
+	return fmt.Sprintf("%s.r%d", id, revision)
+}
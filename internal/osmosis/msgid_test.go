@@ -0,0 +1,64 @@
+package osmosis
+
+import "testing"
+
+func Test_poolsMsgID(t *testing.T) {
+	a := poolsMsgID("state.pools", 100, []uint64{3, 1, 2})
+	b := poolsMsgID("state.pools", 100, []uint64{1, 2, 3})
+	if a != b {
+		t.Errorf("ids differ by pool order: %q != %q", a, b)
+	}
+	if a != "state.pools.100.1,2,3" {
+		t.Errorf("unexpected id %q", a)
+	}
+	if poolsMsgID("volume.pool", 100, []uint64{1, 2, 3}) == a {
+		t.Errorf("ids of different subjects must differ")
+	}
+	if poolsMsgID("state.pools", 101, []uint64{1, 2, 3}) == a {
+		t.Errorf("ids of different heights must differ")
+	}
+}
+
+func Test_poolMsgID(t *testing.T) {
+	// With a single monitored pool the aggregated and the per-pool messages must not share the id,
+	// since a stream capturing both subjects would drop one of them.
+	aggregated := poolsMsgID("volume.pool", 100, []uint64{5})
+	pool := poolMsgID("volume.pool", 5, 100)
+	if pool == aggregated {
+		t.Errorf("per-pool id %q equals the aggregated id", pool)
+	}
+	if pool != "volume.pool.5.100" {
+		t.Errorf("unexpected id %q", pool)
+	}
+	if poolMsgID("state.pool", 5, 100) == pool || poolMsgID("volume.pool", 5, 101) == pool {
+		t.Errorf("ids of different subjects or heights must differ")
+	}
+}
+
+func Test_revisedMsgID(t *testing.T) {
+	id := poolsMsgID("state.pools", 100, []uint64{1, 2, 3})
+	if revisedMsgID(id, 0) != id {
+		t.Errorf("the first revision must keep the id")
+	}
+	if revisedMsgID(id, 1) == id || revisedMsgID(id, 1) == revisedMsgID(id, 2) {
+		t.Errorf("ids of revisions must differ")
+	}
+}
+
+func Test_blockMsgID(t *testing.T) {
+	if blockMsgID("osmosis-1", 100) == blockMsgID("osmo-test-5", 100) {
+		t.Errorf("ids of different chains must differ")
+	}
+	if blockMsgID("osmosis-1", 100) != blockMsgID("osmosis-1", 100) {
+		t.Errorf("ids must be deterministic")
+	}
+}
+
+func Test_swapMsgID(t *testing.T) {
+	if swapMsgID("A", 0) == swapMsgID("A", 1) {
+		t.Errorf("ids of different swaps of a transaction must differ")
+	}
+	if swapMsgID("A", 0) == txMsgID("A") {
+		t.Errorf("swap and transaction ids must differ")
+	}
+}
@@ -13,22 +13,69 @@ var (
 	TendermintAPIParam = "tm"
 	RPCAPIParam        = "rpc"
 	GRPCAPIParam       = "grpc"
+	GRPCArchiveParam   = "grpc_archive"
+	ArchiveDepthParam  = "archive_depth"
+	ConcurrencyParam   = "rpc_concurrency"
+	RateLimitParam     = "rpc_rate_limit"
+	GRPCTLSParam       = "grpc_tls"
+	GRPCHeadersParam   = "grpc_headers"
+	TMHeadersParam     = "tm_headers"
+	TMUserParam        = "tm_user"
+	TMPasswordParam    = "tm_password"
+	EventQueriesParam  = "event_queries"
 	MempoolPeriodParam = "mmp"
+	BlockDeadlineParam = "block_deadline"
+	MaxCatchupParam    = "max_catchup"
+	SpillDirParam      = "spill_dir"
+	SpillMaxBytesParam = "spill_max_bytes"
 	PoolIdsParam       = "pids"
 	BlocksToIndexParam = "bti"
 	PriceSubjectParam  = "prices"
 	MetricsParam       = "metrics"
+	APIParam           = "api"
 	SocketAddrParam    = "socket"
+	StreamParamPrefix  = "stream_"
+	TxSubjectsParam    = "tx_subjects"
+	PoolSubjectsParam  = "pool_subjects"
+	QueryParam         = "query"
 )
 
-func WithTendermintAPI(url string) options.Option {
+// Subjects transactions are published to.
+const (
+	// TxSubjectsSingle publishes every transaction to {prefix}.{name}.tx only.
+	TxSubjectsSingle = "tx"
+	// TxSubjectsTypes publishes transactions to {prefix}.{name}.tx.{message type} only.
+	TxSubjectsTypes = "types"
+	// TxSubjectsBoth publishes transactions to both.
+	TxSubjectsBoth = "both"
+
+	// txTypeUnknown is the message type subject token of transactions that could not be decoded.
+	txTypeUnknown = "unknown"
+)
+
+// Published streams that can be turned off.
+const (
+	StreamBlock      = "block"
+	StreamTx         = "tx"
+	StreamMempool    = "mempool"
+	StreamStatePools = "state.pools"
+	StreamVolumePool = "volume.pool"
+	StreamSwaps      = "swaps"
+)
+
+// Streams lists all the streams that can be turned off.
+var Streams = []string{StreamBlock, StreamTx, StreamMempool, StreamStatePools, StreamVolumePool, StreamSwaps}
+
+// WithTendermintAPI sets Tendermint RPC endpoints. Queries and the websocket subscription
+// fail over to the next healthy endpoint when the current one fails.
+func WithTendermintAPI(urls []string) options.Option {
 	return func(o *options.Options) {
-		service.WithParam(TendermintAPIParam, url)(o)
+		service.WithParam(TendermintAPIParam, urls)(o)
 	}
 }
 
-func (p *Publisher) TendermintApi() string {
-	return options.Param(p.Options, TendermintAPIParam, "tcp://localhost:26657")
+func (p *Publisher) TendermintApi() []string {
+	return options.Param(p.Options, TendermintAPIParam, []string{"tcp://localhost:26657"})
 }
 
 func WithRPCAPI(url string) options.Option {
@@ -41,14 +88,185 @@ func (p *Publisher) RPCApi() string {
 	return options.Param(p.Options, RPCAPIParam, "http://localhost:1317")
 }
 
-func WithGRPCAPI(url string) options.Option {
+// WithGRPCAPI sets gRPC endpoints. Queries fail over to the next healthy endpoint when the current one fails.
+func WithGRPCAPI(urls []string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(GRPCAPIParam, urls)(o)
+	}
+}
+
+func (p *Publisher) GRPCApi() []string {
+	return options.Param(p.Options, GRPCAPIParam, []string{"localhost:9090"})
+}
+
+// WithGRPCArchiveAPI sets gRPC endpoints of archive nodes that are used for historical state queries.
+func WithGRPCArchiveAPI(urls []string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(GRPCArchiveParam, urls)(o)
+	}
+}
+
+func (p *Publisher) GRPCArchiveApi() []string {
+	return options.Param(p.Options, GRPCArchiveParam, []string{})
+}
+
+// WithArchiveDepth sets how many blocks behind the latest height a state query has to be
+// in order to be routed to the archive endpoints. Zero routes only queries for pruned heights.
+func WithArchiveDepth(blocks uint64) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(ArchiveDepthParam, blocks)(o)
+	}
+}
+
+func (p *Publisher) ArchiveDepth() uint64 {
+	return options.Param(p.Options, ArchiveDepthParam, uint64(0))
+}
+
+// WithGRPCTLS enables TLS on gRPC connections.
+func WithGRPCTLS(cfg *TLSConfig) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(GRPCTLSParam, cfg)(o)
+	}
+}
+
+func (p *Publisher) GRPCTLS() *TLSConfig {
+	return options.Param(p.Options, GRPCTLSParam, (*TLSConfig)(nil))
+}
+
+// WithGRPCHeaders sets metadata that is sent with every gRPC call, e.g. API keys.
+func WithGRPCHeaders(headers map[string]string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(GRPCHeadersParam, headers)(o)
+	}
+}
+
+func (p *Publisher) GRPCHeaders() map[string]string {
+	return options.Param(p.Options, GRPCHeadersParam, map[string]string{})
+}
+
+// WithTendermintHeaders sets HTTP headers that are sent with every Tendermint RPC request.
+func WithTendermintHeaders(headers map[string]string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(TMHeadersParam, headers)(o)
+	}
+}
+
+func (p *Publisher) TendermintHeaders() map[string]string {
+	return options.Param(p.Options, TMHeadersParam, map[string]string{})
+}
+
+// WithTendermintBasicAuth sets basic auth credentials of Tendermint RPC requests.
+func WithTendermintBasicAuth(user, password string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(TMUserParam, user)(o)
+		service.WithParam(TMPasswordParam, password)(o)
+	}
+}
+
+func (p *Publisher) TendermintBasicAuth() (string, string) {
+	return options.Param(p.Options, TMUserParam, ""), options.Param(p.Options, TMPasswordParam, "")
+}
+
+// WithEventQueries sets Tendermint queries that select the transactions whose events trigger publishing of the involved pools.
+func WithEventQueries(queries []string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(EventQueriesParam, queries)(o)
+	}
+}
+
+// EventQueries returns configured event queries or DefaultEventQueries if none are configured.
+func (p *Publisher) EventQueries() []string {
+	queries := options.Param(p.Options, EventQueriesParam, []string{})
+	if len(queries) == 0 {
+		return DefaultEventQueries
+	}
+	return queries
+}
+
+// WithStream enables or disables publishing of the stream. All streams are enabled by default.
+func WithStream(stream string, enabled bool) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(StreamParamPrefix+stream, enabled)(o)
+	}
+}
+
+func (p *Publisher) StreamEnabled(stream string) bool {
+	return options.Param(p.Options, StreamParamPrefix+stream, true)
+}
+
+// poolStreamsEnabled reports whether any of the streams that depend on the pool indexer is enabled.
+func (p *Publisher) poolStreamsEnabled() bool {
+	return p.StreamEnabled(StreamStatePools) || p.StreamEnabled(StreamVolumePool)
+}
+
+// txStreamsEnabled reports whether any of the streams that are built from transactions is enabled.
+func (p *Publisher) txStreamsEnabled() bool {
+	return p.StreamEnabled(StreamTx) || p.StreamEnabled(StreamSwaps)
+}
+
+// WithTxSubjects sets the subjects transactions are published to: TxSubjectsSingle, TxSubjectsTypes or TxSubjectsBoth.
+func WithTxSubjects(mode string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(TxSubjectsParam, mode)(o)
+	}
+}
+
+func (p *Publisher) TxSubjects() string {
+	return options.Param(p.Options, TxSubjectsParam, TxSubjectsSingle)
+}
+
+// TxSocketSubjects returns the subject filters of the legacy transaction socket for the tx subjects mode.
+// The socket mirrors the tx subject, or every message type subject when only those are published,
+// so that a transaction with several message types is mirrored once for each of them.
+func TxSocketSubjects(mode string) []string {
+	if mode == TxSubjectsTypes {
+		return []string{"tx.>"}
+	}
+	return []string{"tx"}
+}
+
+// WithPoolSubjects additionally publishes every pool to its own volume.pool.{id} and state.pool.{id} subjects.
+func WithPoolSubjects(enabled bool) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(PoolSubjectsParam, enabled)(o)
+	}
+}
+
+func (p *Publisher) PoolSubjects() bool {
+	return options.Param(p.Options, PoolSubjectsParam, false)
+}
+
+// WithQuery serves request/reply queries of indexed data on {prefix}.{name}.query.{query}.
+func WithQuery(enabled bool) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(QueryParam, enabled)(o)
+	}
+}
+
+func (p *Publisher) QueryEnabled() bool {
+	return options.Param(p.Options, QueryParam, false)
+}
+
+// WithRPCConcurrency sets how many pool queries can be in flight at the same time.
+func WithRPCConcurrency(n int) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(ConcurrencyParam, n)(o)
+	}
+}
+
+func (p *Publisher) RPCConcurrency() int {
+	return options.Param(p.Options, ConcurrencyParam, 8)
+}
+
+// WithRPCRateLimit sets the maximum number of state queries per second sent to the node. Zero disables the limit.
+func WithRPCRateLimit(queriesPerSecond float64) options.Option {
 	return func(o *options.Options) {
-		service.WithParam(GRPCAPIParam, url)(o)
+		service.WithParam(RateLimitParam, queriesPerSecond)(o)
 	}
 }
 
-func (p *Publisher) GRPCApi() string {
-	return options.Param(p.Options, GRPCAPIParam, "localhost:9090")
+func (p *Publisher) RPCRateLimit() float64 {
+	return options.Param(p.Options, RateLimitParam, float64(100))
 }
 
 func WithMempoolPeriod(d time.Duration) options.Option {
@@ -61,6 +279,52 @@ func (p *Publisher) MempoolPeriod() time.Duration {
 	return options.Param(p.Options, MempoolPeriodParam, time.Millisecond*50)
 }
 
+// WithSpillDir sets the directory of the files that take events when in-memory queues are full.
+func WithSpillDir(dir string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(SpillDirParam, dir)(o)
+	}
+}
+
+// SpillDir returns the spill directory. Empty value means the default temporary directory.
+func (p *Publisher) SpillDir() string {
+	return options.Param(p.Options, SpillDirParam, "")
+}
+
+// WithSpillMaxBytes sets the disk budget of each subscription's spill file. Zero disables spilling.
+func WithSpillMaxBytes(n int64) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(SpillMaxBytesParam, n)(o)
+	}
+}
+
+func (p *Publisher) SpillMaxBytes() int64 {
+	return options.Param(p.Options, SpillMaxBytesParam, int64(256<<20))
+}
+
+// WithMaxCatchupBlocks limits how many missed blocks are backfilled after a restart or a connection loss.
+func WithMaxCatchupBlocks(n uint64) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(MaxCatchupParam, n)(o)
+	}
+}
+
+func (p *Publisher) MaxCatchupBlocks() uint64 {
+	return options.Param(p.Options, MaxCatchupParam, uint64(720))
+}
+
+// WithBlockDeadline sets how long after a block is received its pools of interest may still be published.
+// Computation that is not done by then is skipped. Zero disables the deadline.
+func WithBlockDeadline(d time.Duration) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(BlockDeadlineParam, d)(o)
+	}
+}
+
+func (p *Publisher) BlockDeadline() time.Duration {
+	return options.Param(p.Options, BlockDeadlineParam, time.Second*10)
+}
+
 func WithPoolIds(ids []uint64) options.Option {
 	idsMap := make(map[uint64]struct{}, len(ids))
 	for _, id := range ids {
@@ -108,6 +372,17 @@ func (p *Publisher) MetricsURL() string {
 	return options.Param(p.Options, MetricsParam, "")
 }
 
+// WithAPI serves the HTTP API on addr, e.g. 0.0.0.0:8080. It can be the same address as metrics.
+func WithAPI(addr string) options.Option {
+	return func(o *options.Options) {
+		service.WithParam(APIParam, addr)(o)
+	}
+}
+
+func (p *Publisher) APIAddr() string {
+	return options.Param(p.Options, APIParam, "")
+}
+
 func WithSocketAddr(url string) options.Option {
 	return func(o *options.Options) {
 		service.WithParam(SocketAddrParam, url)(o)
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/dtlWithSocket"
 )
 
 func TestWithPoolIds(t *testing.T) {
@@ -36,3 +37,40 @@ func TestWithPoolIds(t *testing.T) {
 		})
 	}
 }
+
+func TestWithStream(t *testing.T) {
+	var opt options.Options
+
+	err := opt.Parse(WithStream(StreamBlock, false), WithStream(StreamTx, true))
+	if err != nil {
+		t.Errorf("opt.Parse failed: %v", err)
+	}
+
+	p := &Publisher{Service: dtlWithSocket.NewNatsAndSocketConn()}
+	p.Options = opt
+	if p.StreamEnabled(StreamBlock) {
+		t.Errorf("block stream must be disabled")
+	}
+	if !p.StreamEnabled(StreamTx) || !p.StreamEnabled(StreamMempool) {
+		t.Errorf("tx and mempool streams must be enabled")
+	}
+	if !p.poolStreamsEnabled() {
+		t.Errorf("pool streams must be enabled by default")
+	}
+}
+
+func TestTxSocketSubjects(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{TxSubjectsSingle, "tx"},
+		{TxSubjectsBoth, "tx"},
+		{TxSubjectsTypes, "tx.>"},
+	}
+	for _, tt := range tests {
+		if got := TxSocketSubjects(tt.mode); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("TxSocketSubjects(%q) = %v, want [%s]", tt.mode, got, tt.want)
+		}
+	}
+}
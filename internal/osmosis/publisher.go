@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -21,12 +21,12 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type Publisher struct {
 	*dtlWithSocket.Service
 	rpc       *rpc
+	blocks    blockSource
 	db        repository.Repository
 	indexer   indexer.Indexer
 	chainId   string
@@ -40,6 +40,20 @@ type Publisher struct {
 	poolCounter       atomic.Uint64
 	errCounter        atomic.Uint64
 	evtOtherCounter   atomic.Uint64
+	backfillCounter   atomic.Uint64
+	publishedHeight   atomic.Uint64
+	txDoneHeight      atomic.Uint64
+	txHeights         *txHeights
+	txMu              sync.Mutex // held while transactions are published
+	publishedTxs      *recentSet
+	poolBatches       *poolBatcher
+	poolJobs          chan poolJob
+	poolJobsSkipped   atomic.Uint64
+	queryCounter      atomic.Uint64
+	queryErrCounter   atomic.Uint64
+	swapCounter       atomic.Uint64
+	latestBlock       atomic.Pointer[blockStamp]
+	caughtUp          chan struct{}
 
 	// Total counters
 	blocksCounter       prometheus.Counter
@@ -90,19 +104,61 @@ func New(db repository.Repository, opts ...options.Option) (*Publisher, error) {
 			Help: "The current duration in seconds the publisher is running",
 		}),
 		startupTimestamp: time.Now(),
+		publishedTxs:     newRecentSet(publishedTxsDedupSize),
+		txHeights:        newTxHeights(),
+		poolBatches:      newPoolBatcher(),
+		poolJobs:         make(chan poolJob, poolJobsQueueSize),
+		caughtUp:         make(chan struct{}),
 	}
 
-	ret.Configure(opts...)
+	if err := ret.Configure(opts...); err != nil {
+		return nil, err
+	}
+
+	switch mode := ret.TxSubjects(); mode {
+	case TxSubjectsSingle, TxSubjectsTypes, TxSubjectsBoth:
+	default:
+		return nil, fmt.Errorf("unknown tx subjects %q, expected %q, %q or %q", mode, TxSubjectsSingle, TxSubjectsTypes, TxSubjectsBoth)
+	}
 
 	ret.Logger.Info("Tracking pools", "ids", ret.PoolIds())
 
-	rpc, err := newRpc(ret.Context, ret.Cancel, ret.Group, ret.Logger, db, ret.getDenoms, ret.TendermintApi(), ret.GRPCApi())
+	tmUser, tmPassword := ret.TendermintBasicAuth()
+	rpc, err := newRpc(ret.Context, ret.Cancel, ret.Group, ret.Logger, db, ret.getDenoms, rpcConfig{
+		tendermintURLs:   ret.TendermintApi(),
+		grpcURLs:         ret.GRPCApi(),
+		archiveURLs:      ret.GRPCArchiveApi(),
+		archiveDepth:     ret.ArchiveDepth(),
+		concurrency:      ret.RPCConcurrency(),
+		queriesPerSecond: ret.RPCRateLimit(),
+		credentials: credentialsConfig{
+			grpcTLS:            ret.GRPCTLS(),
+			grpcHeaders:        ret.GRPCHeaders(),
+			tendermintHeaders:  ret.TendermintHeaders(),
+			tendermintUser:     tmUser,
+			tendermintPassword: tmPassword,
+		},
+		spillDir:      ret.SpillDir(),
+		spillMaxBytes: ret.SpillMaxBytes(),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed connecting to Osmosis: %w", err)
 	}
 	ret.rpc = rpc
+	ret.blocks = rpc
 
-	indexer, err := indexerimpl.New(ret.Context, ret.Cancel, ret.Group, ret.Logger, db, rpc, ret.PoolIds(), ret.BlocksToIndex(), ret.VerboseLog)
+	// The indexer is still needed for IBC denom traces, but it does not sync pools unless pool streams are enabled.
+	poolIds, blocksToIndex := ret.PoolIds(), ret.BlocksToIndex()
+	if !ret.poolStreamsEnabled() {
+		poolIds, blocksToIndex = nil, 0
+	}
+	for _, stream := range Streams {
+		if !ret.StreamEnabled(stream) {
+			ret.Logger.Info("Stream disabled", "stream", stream)
+		}
+	}
+
+	indexer, err := indexerimpl.New(ret.Context, ret.Cancel, ret.Group, ret.Logger, db, rpc, poolIds, blocksToIndex, ret.VerboseLog)
 	if err != nil {
 		return nil, fmt.Errorf("failed creating an indexer: %w", err)
 	}
@@ -136,70 +192,83 @@ func (p *Publisher) SubscribeAll() error {
 	if err := p.subscribePriceFeed(); err != nil {
 		return fmt.Errorf("failed subscribing to price feed: %w", err)
 	}
-	if err := p.subscribeBlocks(); err != nil {
-		return fmt.Errorf("failed subscribing to blocks: %w", err)
+	// Blocks drive backfilling of transactions and the pool indexer, so they are needed unless only mempool is published.
+	if p.StreamEnabled(StreamBlock) || p.txStreamsEnabled() || p.poolStreamsEnabled() {
+		if err := p.subscribeBlocks(); err != nil {
+			return fmt.Errorf("failed subscribing to blocks: %w", err)
+		}
 	}
-	if err := p.subscribeTransactions(); err != nil {
-		return fmt.Errorf("failed subscribing to txs: %w", err)
+	if p.txStreamsEnabled() {
+		if err := p.subscribeTransactions(); err != nil {
+			return fmt.Errorf("failed subscribing to txs: %w", err)
+		}
 	}
-	if err := p.subscribeOsmosisEvents(); err != nil {
-		return fmt.Errorf("failed subscribing to osmosis events: %w", err)
+	if p.StreamEnabled(StreamStatePools) {
+		if err := p.subscribeOsmosisEvents(); err != nil {
+			return fmt.Errorf("failed subscribing to osmosis events: %w", err)
+		}
 	}
 	return nil
 }
 
 func (p *Publisher) Start() context.Context {
 	p.mempoolMessages.Store(0)
+	p.restoreCheckpoints()
 
 	err := p.SubscribeAll()
 	if err != nil {
 		p.Fail(err)
 		return p.Context
 	}
+	p.Group.Go(p.catchUp)
+	p.Group.Go(p.handleCheckpoints)
+
+	if p.QueryEnabled() {
+		if err := p.serveQueries(); err != nil {
+			p.Fail(err)
+			return p.Context
+		}
+	}
+
+	p.serveHTTP()
 
-	if metricsUrl := p.MetricsURL(); metricsUrl != "" {
-		p.Group.Go(
-			func() error {
-				http.Handle("/metrics", promhttp.Handler())
-				http.ListenAndServe(metricsUrl, nil)
-				return nil
-			},
-		)
+	if p.StreamEnabled(StreamMempool) {
+		p.Group.Go(p.handleMempool)
 	}
+	return p.Service.Start()
+}
 
+// handleMempool periodically publishes transactions of the mempool.
+func (p *Publisher) handleMempool() error {
 	mempoolTicker := time.NewTicker(p.MempoolPeriod())
-	p.Group.Go(
-		func() error {
-			for {
-				select {
-				case <-p.Context.Done():
-					return nil
-				case <-mempoolTicker.C:
-					if p.rpc == nil {
-						continue
-					}
-					pool, err := p.rpc.Mempool()
-					if err != nil {
-						p.Logger.Warn("Mempool failed: ", "err", err)
-						continue
-					}
-					if pool != nil {
-						p.mempoolMessages.Add(uint64(len(pool)))
-						p.mempoolCounter.Add(float64(len(pool)))
-						p.Publish(
-							&types.Mempool{
-								Nonce:        p.NewNonce(),
-								Transactions: pool,
-							},
-							"mempool",
-						)
-						p.messagesCounter.Add(1)
-					}
-				}
+	defer mempoolTicker.Stop()
+	for {
+		select {
+		case <-p.Context.Done():
+			return nil
+		case <-mempoolTicker.C:
+			if p.rpc == nil {
+				continue
 			}
-		},
-	)
-	return p.Service.Start()
+			pool, err := p.rpc.Mempool()
+			if err != nil {
+				p.Logger.Warn("Mempool failed: ", "err", err)
+				continue
+			}
+			if pool != nil {
+				p.mempoolMessages.Add(uint64(len(pool)))
+				p.mempoolCounter.Add(float64(len(pool)))
+				p.Publish(
+					&types.Mempool{
+						Nonce:        p.NewNonce(),
+						Transactions: pool,
+					},
+					"mempool",
+				)
+				p.messagesCounter.Add(1)
+			}
+		}
+	}
 }
 
 func (p *Publisher) Close() error {
@@ -207,6 +276,8 @@ func (p *Publisher) Close() error {
 	p.Cancel(nil)
 	var errArr []error
 
+	errArr = append(errArr, p.saveCheckpoints())
+
 	p.Logger.Info("Publisher.priceFeed.Unsubscribe")
 	errArr = append(errArr, fmt.Errorf("failure during priceFeed.Unsubscribe: %w", p.priceFeed.Unsubscribe()))
 
@@ -229,15 +300,29 @@ func (p *Publisher) Close() error {
 func (p *Publisher) getStatus() map[string]string {
 	p.uptimeGauge.Set(time.Since(p.startupTimestamp).Seconds())
 
-	return map[string]string{
-		"blocks":         strconv.FormatUint(p.blockCounter.Swap(0), 10),
-		"unknown_events": strconv.FormatUint(p.evtOtherCounter.Swap(0), 10),
-		"txs":            strconv.FormatUint(p.txCounter.Swap(0), 10),
-		"pools":          strconv.FormatUint(p.poolCounter.Swap(0), 10),
-		"errors":         strconv.FormatUint(p.errCounter.Swap(0), 10),
-		"mempool.txs":    strconv.FormatUint(p.mempoolMessages.Swap(0), 10),
-		"published":      strconv.FormatUint(p.publishedMessages.Swap(0), 10),
+	status := map[string]string{
+		"blocks":            strconv.FormatUint(p.blockCounter.Swap(0), 10),
+		"unknown_events":    strconv.FormatUint(p.evtOtherCounter.Swap(0), 10),
+		"txs":               strconv.FormatUint(p.txCounter.Swap(0), 10),
+		"pools":             strconv.FormatUint(p.poolCounter.Swap(0), 10),
+		"errors":            strconv.FormatUint(p.errCounter.Swap(0), 10),
+		"mempool.txs":       strconv.FormatUint(p.mempoolMessages.Swap(0), 10),
+		"published":         strconv.FormatUint(p.publishedMessages.Swap(0), 10),
+		"blocks_backfilled": strconv.FormatUint(p.backfillCounter.Swap(0), 10),
+		"pool_jobs_skipped": strconv.FormatUint(p.poolJobsSkipped.Swap(0), 10),
+		"pool_jobs_queue":   strconv.Itoa(len(p.poolJobs)),
+		"query.requests":    strconv.FormatUint(p.queryCounter.Swap(0), 10),
+		"query.errors":      strconv.FormatUint(p.queryErrCounter.Swap(0), 10),
+		"swaps":             strconv.FormatUint(p.swapCounter.Swap(0), 10),
+	}
+	for _, stream := range Streams {
+		state := "enabled"
+		if !p.StreamEnabled(stream) {
+			state = "disabled"
+		}
+		status["stream."+stream] = state
 	}
+	return status
 }
 
 // DiagnosticsObtainLiquidity sequentially retrieves pool Liquidity from minHeight till maxHeight.
@@ -301,35 +386,6 @@ func (p *Publisher) DiagnosticsObtainVolume(poolId uint64, minHeight, maxHeight
 	return volumes
 }
 
-func (p *Publisher) MakeSentinel(timeout time.Duration) func() error {
-	sentinel := time.NewTimer(timeout)
-	lastEvent := time.Now()
-	p.Group.Go(
-		func() error {
-			defer sentinel.Stop()
-			for {
-				select {
-				case <-p.Context.Done():
-					p.Logger.Info("sentinel: c.Context Done")
-					return nil
-				case <-sentinel.C:
-					err := fmt.Errorf("event subscription timed out, last seen: %s", time.Since(lastEvent))
-					return err
-				}
-			}
-		},
-	)
-
-	return func() error {
-		if !sentinel.Stop() {
-			return fmt.Errorf("event subscription timed out while resetting, last seen: %s", time.Since(lastEvent))
-		}
-		sentinel.Reset(timeout)
-		lastEvent = time.Now()
-		return nil
-	}
-}
-
 func (p *Publisher) getDenoms(ibcTrace IBCDenomTrace) error {
 	for denom := range ibcTrace {
 		res, err := p.indexer.DenomTrace(denom)
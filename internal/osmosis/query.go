@@ -0,0 +1,310 @@
+package osmosis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/osmosis-publisher/pkg/indexer"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+	"github.com/synternet/osmosis-publisher/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Queries served on {prefix}.{name}.query.{query}.
+const (
+	QueryPoolStatus = "pool_status"
+	QueryPriceAt    = "price_at"
+	QueryDenomTrace = "denom_trace"
+
+	// queryMaxPools limits the number of pools of a single pool_status query.
+	queryMaxPools = 100
+)
+
+// Codes of QueryError.
+const (
+	QueryErrBadRequest = "bad_request"
+	QueryErrNotFound   = "not_found"
+	QueryErrInternal   = "internal"
+)
+
+// QueryError is the error of a failed query.
+type QueryError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *QueryError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func queryError(code string, format string, args ...any) *QueryError {
+	return &QueryError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// QueryResponse is the reply to a query. Either Result or Error is set.
+type QueryResponse struct {
+	Result any         `json:"result,omitempty"`
+	Error  *QueryError `json:"error,omitempty"`
+}
+
+// PoolStatusQuery requests liquidity and volume of pools at a height. Zero height is the latest height.
+type PoolStatusQuery struct {
+	PoolIds []uint64 `json:"pool_ids"`
+	Height  uint64   `json:"height,omitempty"`
+}
+
+type PoolStatusResult struct {
+	Height uint64             `json:"height"`
+	Pools  []types.PoolStatus `json:"pools"`
+	// NotFound lists the requested pools that do not exist at the height.
+	NotFound []uint64      `json:"not_found,omitempty"`
+	Metadata IBCDenomTrace `json:"metadata,omitempty"`
+}
+
+// PriceAtQuery requests the price of a denom, e.g. "uosmo", at a time. Zero timestamp is now.
+type PriceAtQuery struct {
+	Denom     string    `json:"denom"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+type PriceAtResult struct {
+	Denom     string    `json:"denom"`
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	// EstimationError is the distance in seconds to the prices the value was estimated from.
+	EstimationError float64 `json:"estimation_error"`
+}
+
+// DenomTraceQuery requests the trace of an IBC denom, e.g. "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2".
+type DenomTraceQuery struct {
+	Denom string `json:"denom"`
+}
+
+type DenomTraceResult struct {
+	Denom string              `json:"denom"`
+	Trace ibctypes.DenomTrace `json:"trace"`
+}
+
+// serveQueries subscribes to all the queries.
+func (p *Publisher) serveQueries() error {
+	handlers := map[string]func(data []byte) (any, error){
+		QueryPoolStatus: p.queryPoolStatus,
+		QueryPriceAt:    p.queryPriceAt,
+		QueryDenomTrace: p.queryDenomTrace,
+	}
+	for name, handler := range handlers {
+		name, handler := name, handler
+		_, err := p.ServeBuf(func(data []byte) []byte { return p.handleQuery(name, handler, data) }, "query", name)
+		if err != nil {
+			return fmt.Errorf("failed serving %s query: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// handleQuery runs the query and encodes the reply. Errors that are not a QueryError are internal.
+func (p *Publisher) handleQuery(name string, handler func(data []byte) (any, error), data []byte) []byte {
+	p.queryCounter.Add(1)
+	var response QueryResponse
+	result, err := handler(data)
+	if err != nil {
+		p.queryErrCounter.Add(1)
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) {
+			p.Logger.Error("Query failed", "query", name, "err", err)
+			queryErr = queryError(QueryErrInternal, "query failed")
+		}
+		response.Error = queryErr
+	} else {
+		response.Result = result
+	}
+
+	reply, err := json.Marshal(response)
+	if err != nil {
+		p.Logger.Error("Encoding query reply failed", "query", name, "err", err)
+		reply, _ = json.Marshal(QueryResponse{Error: queryError(QueryErrInternal, "encoding reply failed")})
+	}
+	return reply
+}
+
+func decodeQuery(data []byte, query any) error {
+	if err := json.Unmarshal(data, query); err != nil {
+		return queryError(QueryErrBadRequest, "bad request: %s", err)
+	}
+	return nil
+}
+
+func (p *Publisher) queryPoolStatus(data []byte) (any, error) {
+	var query PoolStatusQuery
+	if err := decodeQuery(data, &query); err != nil {
+		return nil, err
+	}
+	if len(query.PoolIds) == 0 || len(query.PoolIds) > queryMaxPools {
+		return nil, queryError(QueryErrBadRequest, "pool_ids must list 1 to %d pools", queryMaxPools)
+	}
+
+	statuses, height, err := p.indexer.QueryPoolStatusesAt(query.Height, query.PoolIds...)
+	if errors.Is(err, indexer.ErrHeightNotIndexed) {
+		return nil, queryError(QueryErrBadRequest, "%s", err)
+	}
+	statuses, notFound, err := splitPoolErrors(statuses, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		return nil, queryError(QueryErrNotFound, "unknown pools %v at height %d", notFound, height)
+	}
+	if err := p.indexer.CalculateVolumes(statuses); err != nil {
+		p.Logger.Warn("Calculating volumes of query failed", "err", err)
+	}
+
+	metadata := make(IBCDenomTrace)
+	for _, status := range statuses {
+		for _, coin := range status.TotalLiquidity {
+			metadata.Add(coin.Denom)
+		}
+		for _, volume := range status.Volumes {
+			for _, coin := range volume.Volume {
+				metadata.Add(coin.Denom)
+			}
+		}
+	}
+	p.getDenoms(metadata)
+
+	return PoolStatusResult{
+		Height:   height,
+		Pools:    statuses,
+		NotFound: notFound,
+		Metadata: metadata,
+	}, nil
+}
+
+// splitPoolErrors drops the statuses of the pools that failed and returns the ids of the pools the node does not know.
+// A pruned height is a bad request, other failures are internal errors.
+func splitPoolErrors(statuses []types.PoolStatus, err error) ([]types.PoolStatus, []uint64, error) {
+	failed := make(map[uint64]bool)
+	var notFound []uint64
+	for _, poolErr := range poolErrors(err) {
+		switch {
+		case isPrunedHeightError(poolErr):
+			return nil, nil, queryError(QueryErrBadRequest, "state of the height is pruned")
+		case status.Code(poolErr) == codes.NotFound:
+			if !failed[poolErr.PoolId] {
+				notFound = append(notFound, poolErr.PoolId)
+			}
+			failed[poolErr.PoolId] = true
+		default:
+			return nil, nil, err
+		}
+	}
+	if len(failed) == 0 && err != nil {
+		return nil, nil, err
+	}
+
+	found := make([]types.PoolStatus, 0, len(statuses))
+	for _, s := range statuses {
+		if s.PoolId != 0 && !failed[s.PoolId] {
+			found = append(found, s)
+		}
+	}
+	return found, notFound, nil
+}
+
+// poolErrors returns all the PoolError in the tree of err.
+func poolErrors(err error) []*PoolError {
+	switch e := err.(type) {
+	case *PoolError:
+		return []*PoolError{e}
+	case interface{ Unwrap() []error }:
+		var ret []*PoolError
+		for _, err := range e.Unwrap() {
+			ret = append(ret, poolErrors(err)...)
+		}
+		return ret
+	case interface{ Unwrap() error }:
+		return poolErrors(e.Unwrap())
+	}
+	return nil
+}
+
+// queryPriceAt estimates the price from the prices cached by the indexer.
+// Older prices are looked up in the repository.
+func (p *Publisher) queryPriceAt(data []byte) (any, error) {
+	var query PriceAtQuery
+	if err := decodeQuery(data, &query); err != nil {
+		return nil, err
+	}
+	if query.Denom == "" {
+		return nil, queryError(QueryErrBadRequest, "denom is required")
+	}
+	if query.Timestamp.IsZero() {
+		query.Timestamp = time.Now()
+	}
+
+	result := PriceAtResult{
+		Denom:     query.Denom,
+		Timestamp: query.Timestamp,
+	}
+	if value, estimationError, ok := p.indexer.PriceAt(query.Timestamp, query.Denom); ok {
+		result.Value = value
+		result.EstimationError = estimationError.Seconds()
+		return result, nil
+	}
+
+	prices, found := p.db.NearestTokenPrice(query.Timestamp, query.Denom)
+	if !found || len(prices) == 0 {
+		return nil, queryError(QueryErrNotFound, "no prices of %s", query.Denom)
+	}
+	nearest := nearestTokenPrice(query.Timestamp, prices)
+	result.Value = nearest.Value
+	result.EstimationError = absDuration(query.Timestamp.Sub(nearest.LastUpdated)).Seconds()
+	return result, nil
+}
+
+func (p *Publisher) queryDenomTrace(data []byte) (any, error) {
+	var query DenomTraceQuery
+	if err := decodeQuery(data, &query); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(strings.ToLower(query.Denom), "ibc/") {
+		return nil, queryError(QueryErrBadRequest, "denom must be an IBC denom ibc/{hash}")
+	}
+
+	trace, err := p.indexer.DenomTrace(query.Denom)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, queryError(QueryErrNotFound, "unknown denom %s", query.Denom)
+		case codes.InvalidArgument:
+			return nil, queryError(QueryErrBadRequest, "invalid denom %s", query.Denom)
+		}
+		return nil, err
+	}
+	return DenomTraceResult{
+		Denom: query.Denom,
+		Trace: trace,
+	}, nil
+}
+
+// nearestTokenPrice returns the price of prices nearest to timestamp. prices must not be empty.
+func nearestTokenPrice(timestamp time.Time, prices []repository.TokenPrice) repository.TokenPrice {
+	nearest := prices[0]
+	for _, price := range prices[1:] {
+		if absDuration(timestamp.Sub(price.LastUpdated)) < absDuration(timestamp.Sub(nearest.LastUpdated)) {
+			nearest = price
+		}
+	}
+	return nearest
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,166 @@
+package osmosis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/osmosis-publisher/pkg/dtlWithSocket"
+	"github.com/synternet/osmosis-publisher/pkg/indexer"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+	"github.com/synternet/osmosis-publisher/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type queryIndexer struct {
+	indexer.Indexer
+}
+
+func (queryIndexer) DenomTrace(ibc string) (ibctypes.DenomTrace, error) {
+	if ibc != "ibc/A" {
+		return ibctypes.DenomTrace{}, status.Error(codes.NotFound, "denomination trace not found")
+	}
+	return ibctypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"}, nil
+}
+
+func (queryIndexer) PriceAt(timestamp time.Time, denom string) (float64, time.Duration, bool) {
+	if denom != "uosmo" {
+		return 0, 0, false
+	}
+	return 0.5, time.Minute, true
+}
+
+func (queryIndexer) QueryPoolStatusesAt(height uint64, poolId ...uint64) ([]types.PoolStatus, uint64, error) {
+	if height > 100 {
+		return nil, 0, fmt.Errorf("%w: height %d is outside of 1-100", indexer.ErrHeightNotIndexed, height)
+	}
+	statuses := make([]types.PoolStatus, len(poolId))
+	var errs []error
+	for i, id := range poolId {
+		switch id {
+		case 404:
+			errs = append(errs, &PoolError{PoolId: id, Err: status.Error(codes.NotFound, "pool not found")})
+			continue
+		case 410:
+			errs = append(errs, &PoolError{PoolId: id, Err: status.Error(codes.InvalidArgument, "version does not exist")})
+			continue
+		case 500:
+			errs = append(errs, &PoolError{PoolId: id, Err: status.Error(codes.Unavailable, "connection refused")})
+			continue
+		}
+		statuses[i] = types.PoolStatus{
+			PoolId:         id,
+			TotalLiquidity: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("uosmo", 1)),
+			Volumes:        []types.PoolStatusVolumeAt{{BlockHeight: 100, Volume: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("ibc/A", 1))}},
+		}
+	}
+	return statuses, 100, errors.Join(errs...)
+}
+
+func (queryIndexer) CalculateVolumes(poolStatuses []types.PoolStatus) error {
+	return nil
+}
+
+type queryRepository struct {
+	repository.Repository
+	prices []repository.TokenPrice
+}
+
+func (r queryRepository) NearestTokenPrice(timestamp time.Time, denom string) ([]repository.TokenPrice, bool) {
+	return r.prices, len(r.prices) > 0
+}
+
+func TestQueries(t *testing.T) {
+	now := time.Now()
+	p := &Publisher{
+		Service: dtlWithSocket.NewNatsAndSocketConn(),
+		indexer: queryIndexer{},
+		db: queryRepository{prices: []repository.TokenPrice{
+			{LastUpdated: now.Add(-time.Hour), Value: 1},
+			{LastUpdated: now.Add(-time.Second), Value: 2},
+		}},
+	}
+	p.Logger = slog.Default()
+
+	tests := []struct {
+		name    string
+		query   string
+		data    string
+		want    string
+		wantErr string
+	}{
+		{"bad json", QueryPoolStatus, `{`, "", QueryErrBadRequest},
+		{"no pools", QueryPoolStatus, `{"pool_ids":[]}`, "", QueryErrBadRequest},
+		{"pools", QueryPoolStatus, `{"pool_ids":[1]}`, "", ""},
+		{"some unknown pools", QueryPoolStatus, `{"pool_ids":[1,404]}`, "", ""},
+		{"unknown pools", QueryPoolStatus, `{"pool_ids":[404]}`, "", QueryErrNotFound},
+		{"height not indexed", QueryPoolStatus, `{"pool_ids":[1],"height":101}`, "", QueryErrBadRequest},
+		{"height pruned", QueryPoolStatus, `{"pool_ids":[1,410]}`, "", QueryErrBadRequest},
+		{"pool failed", QueryPoolStatus, `{"pool_ids":[1,500]}`, "", QueryErrInternal},
+		{"cached price", QueryPriceAt, `{"denom":"uosmo"}`, `0.5`, ""},
+		{"stored price", QueryPriceAt, `{"denom":"uatom"}`, `2`, ""},
+		{"no denom", QueryPriceAt, `{}`, "", QueryErrBadRequest},
+		{"trace", QueryDenomTrace, `{"denom":"ibc/A"}`, "", ""},
+		{"unknown trace", QueryDenomTrace, `{"denom":"ibc/B"}`, "", QueryErrNotFound},
+		{"not ibc", QueryDenomTrace, `{"denom":"uosmo"}`, "", QueryErrBadRequest},
+	}
+	handlers := map[string]func(data []byte) (any, error){
+		QueryPoolStatus: p.queryPoolStatus,
+		QueryPriceAt:    p.queryPriceAt,
+		QueryDenomTrace: p.queryDenomTrace,
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response struct {
+				Result json.RawMessage `json:"result"`
+				Error  *QueryError     `json:"error"`
+			}
+			reply := p.handleQuery(tt.query, handlers[tt.query], []byte(tt.data))
+			if err := json.Unmarshal(reply, &response); err != nil {
+				t.Fatalf("bad reply %s: %v", reply, err)
+			}
+			if tt.wantErr != "" {
+				if response.Error == nil || response.Error.Code != tt.wantErr {
+					t.Fatalf("reply = %s, want %s error", reply, tt.wantErr)
+				}
+				return
+			}
+			if response.Error != nil || response.Result == nil {
+				t.Fatalf("reply = %s, want result", reply)
+			}
+			if tt.want != "" {
+				var result PriceAtResult
+				json.Unmarshal(response.Result, &result)
+				if got, _ := json.Marshal(result.Value); string(got) != tt.want {
+					t.Errorf("value = %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+
+	var result PoolStatusResult
+	json.Unmarshal(p.handleQuery(QueryPoolStatus, p.queryPoolStatus, []byte(`{"pool_ids":[1]}`)), &struct {
+		Result *PoolStatusResult `json:"result"`
+	}{&result})
+	if result.Height != 100 || len(result.Pools) != 1 || result.Metadata["ibc/A"].BaseDenom != "uatom" {
+		t.Errorf("pool_status result = %+v", result)
+	}
+
+	result = PoolStatusResult{}
+	json.Unmarshal(p.handleQuery(QueryPoolStatus, p.queryPoolStatus, []byte(`{"pool_ids":[404,1]}`)), &struct {
+		Result *PoolStatusResult `json:"result"`
+	}{&result})
+	if len(result.Pools) != 1 || result.Pools[0].PoolId != 1 || !reflect.DeepEqual(result.NotFound, []uint64{404}) {
+		t.Errorf("pool_status result = %+v, want pool 1 and not found 404", result)
+	}
+	if p.queryErrCounter.Load() != 9 {
+		t.Errorf("query errors = %d, want 9", p.queryErrCounter.Load())
+	}
+}
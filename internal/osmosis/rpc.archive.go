@@ -0,0 +1,55 @@
+package osmosis
+
+import (
+	"strings"
+)
+
+// prunedHeightErrors are fragments of errors returned by a node that no longer has the state at the requested height.
+var prunedHeightErrors = []string{
+	"version does not exist",
+	"has been pruned",
+	"failed to load state at height",
+}
+
+func isPrunedHeightError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, fragment := range prunedHeightErrors {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// useArchive reports whether a state query at height should go straight to the archive endpoints.
+func (c *rpc) useArchive(height int64) bool {
+	if c.archives == nil || height <= 0 || c.archiveDepth == 0 {
+		return false
+	}
+	return int64(c.latestHeight.Load())-height > int64(c.archiveDepth)
+}
+
+// stateQuery runs a rate limited gRPC state query at height on the primary endpoints. The archive endpoints are used instead
+// if the height is older than the archive depth or the primary endpoints have already pruned it.
+func stateQuery[R any](c *rpc, height int64, call func(client *grpcClient) (R, error)) (R, error) {
+	if err := c.limiter.Wait(c.ctx); err != nil {
+		var zero R
+		return zero, err
+	}
+
+	if c.useArchive(height) {
+		c.archiveCounter.Add(1)
+		return failover(c.archives, call)
+	}
+
+	res, err := failover(c.grpcs, call)
+	if c.archives != nil && height > 0 && isPrunedHeightError(err) {
+		c.logger.Debug("Height pruned, querying archive", "height", height, "err", err)
+		c.archiveCounter.Add(1)
+		return failover(c.archives, call)
+	}
+	return res, err
+}
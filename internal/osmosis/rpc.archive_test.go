@@ -0,0 +1,55 @@
+package osmosis
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRpc_useArchive(t *testing.T) {
+	archives, err := newEndpointPool([]string{"archive"}, func(url string) (*grpcClient, error) { return &grpcClient{}, nil })
+	if err != nil {
+		t.Fatalf("newEndpointPool failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		archives endpointPool[*grpcClient]
+		depth    uint64
+		height   int64
+		want     bool
+	}{
+		{"no archive", nil, 100, 1, false},
+		{"latest", archives, 100, 0, false},
+		{"recent", archives, 100, 950, false},
+		{"old", archives, 100, 899, true},
+		{"pruned only", archives, 0, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &rpc{
+				archives:     tt.archives,
+				archiveDepth: tt.depth,
+			}
+			c.latestHeight.Store(1000)
+			if got := c.useArchive(tt.height); got != tt.want {
+				t.Errorf("rpc.useArchive(%d) = %v, want %v", tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPrunedHeightError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("rpc error: code = InvalidArgument desc = failed to load state at height 100; version does not exist (latest height: 2000)"), true},
+		{errors.New("rpc error: code = Unavailable desc = connection refused"), false},
+	}
+	for _, tt := range tests {
+		if got := isPrunedHeightError(tt.err); got != tt.want {
+			t.Errorf("isPrunedHeightError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
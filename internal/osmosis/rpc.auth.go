@@ -0,0 +1,144 @@
+package osmosis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// TLSConfig configures TLS of gRPC connections. Empty CAFile uses the system certificate pool.
+// CertFile and KeyFile are only needed when the node requires client certificates.
+type TLSConfig struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// credentialsConfig holds credentials used to connect to the node.
+type credentialsConfig struct {
+	grpcTLS            *TLSConfig
+	grpcHeaders        map[string]string
+	tendermintHeaders  map[string]string
+	tendermintUser     string
+	tendermintPassword string
+}
+
+func (cfg *TLSConfig) transportCredentials() (credentials.TransportCredentials, error) {
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// grpcDialOptions returns transport credentials and the interceptors that attach static metadata to every call.
+func (c credentialsConfig) grpcDialOptions() ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if c.grpcTLS != nil {
+		var err error
+		creds, err = c.grpcTLS.transportCredentials()
+		if err != nil {
+			return nil, err
+		}
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+
+	if len(c.grpcHeaders) == 0 {
+		return opts, nil
+	}
+
+	kv := make([]string, 0, len(c.grpcHeaders)*2)
+	for k, v := range c.grpcHeaders {
+		kv = append(kv, k, v)
+	}
+	opts = append(opts,
+		grpc.WithChainUnaryInterceptor(unaryMetadataInterceptor(kv)),
+		grpc.WithChainStreamInterceptor(streamMetadataInterceptor(kv)),
+	)
+	return opts, nil
+}
+
+// unaryMetadataInterceptor appends kv to the outgoing metadata so that the height set by ContextWithHeight is preserved.
+func unaryMetadataInterceptor(kv []string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, kv...), method, req, reply, cc, opts...)
+	}
+}
+
+func streamMetadataInterceptor(kv []string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(metadata.AppendToOutgoingContext(ctx, kv...), desc, cc, method, opts...)
+	}
+}
+
+// newTendermintClient creates a Tendermint RPC client that sends the configured headers and basic auth.
+// Its websocket is not used, since the handshake cannot carry them; subscriptions go through dialEvents.
+func (c credentialsConfig) newTendermintClient(url string) (*rpchttp.HTTP, error) {
+	httpClient, err := jsonrpcclient.DefaultHTTPClient(url)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = time.Second * 3
+
+	if len(c.tendermintHeaders) > 0 || c.tendermintUser != "" {
+		httpClient.Transport = &authTransport{
+			base:     httpClient.Transport,
+			headers:  c.tendermintHeaders,
+			user:     c.tendermintUser,
+			password: c.tendermintPassword,
+		}
+	}
+	return rpchttp.NewWithClient(url, "/websocket", httpClient)
+}
+
+// authTransport adds static headers and basic auth to every request.
+type authTransport struct {
+	base     http.RoundTripper
+	headers  map[string]string
+	user     string
+	password string
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	if t.user != "" {
+		req.SetBasicAuth(t.user, t.password)
+	}
+	return t.base.RoundTrip(req)
+}
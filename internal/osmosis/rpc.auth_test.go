@@ -0,0 +1,136 @@
+package osmosis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryMetadataInterceptor(t *testing.T) {
+	interceptor := unaryMetadataInterceptor([]string{"x-api-key", "secret"})
+
+	ctx := ContextWithHeight(context.Background(), 123)
+	err := interceptor(ctx, "/method", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if got := md.Get("x-api-key"); len(got) != 1 || got[0] != "secret" {
+			t.Errorf("x-api-key = %v, want [secret]", got)
+		}
+		if got := md.Get(grpctypes.GRPCBlockHeightHeader); len(got) != 1 || got[0] != "123" {
+			t.Errorf("%s = %v, want [123]", grpctypes.GRPCBlockHeightHeader, got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+}
+
+func TestAuthTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "pass" {
+			t.Errorf("basic auth = %q %q %v, want user pass", user, password, ok)
+		}
+		if got := r.Header.Get("X-Api-Key"); got != "secret" {
+			t.Errorf("X-Api-Key = %q, want secret", got)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &authTransport{
+			base:     http.DefaultTransport,
+			headers:  map[string]string{"X-Api-Key": "secret"},
+			user:     "user",
+			password: "pass",
+		},
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestDialEvents(t *testing.T) {
+	const query = "tm.event='NewBlock'"
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "pass" || r.Header.Get("X-Api-Key") != "secret" || r.URL.Path != "/websocket" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req rpctypes.RPCRequest
+		if err := conn.ReadJSON(&req); err != nil || req.Method != "subscribe" {
+			t.Errorf("request = %v, %v, want subscribe", req, err)
+			return
+		}
+		conn.WriteJSON(rpctypes.NewRPCSuccessResponse(req.ID, struct{}{}))
+		block := tmtypes.MakeBlock(7, nil, &tmtypes.Commit{}, nil)
+		eventID := rpctypes.JSONRPCStringID(fmt.Sprintf("%v#event", req.ID))
+		conn.WriteJSON(rpctypes.NewRPCSuccessResponse(eventID, ctypes.ResultEvent{Query: query, Data: tmtypes.EventDataNewBlock{Block: block}}))
+
+		// The node rejects subscriptions beyond max_subscriptions_per_client.
+		if err := conn.ReadJSON(&req); err != nil || req.Method != "subscribe" {
+			t.Errorf("request = %v, %v, want subscribe", req, err)
+			return
+		}
+		conn.WriteJSON(rpctypes.RPCInternalError(req.ID, errors.New("max_subscriptions_per_client 1 reached")))
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+	addr := strings.Replace(server.URL, "http://", "tcp://user:pass@", 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := (credentialsConfig{}).dialEvents(ctx, server.URL, slog.Default()); err == nil {
+		t.Fatalf("dialEvents must fail without credentials")
+	}
+
+	creds := credentialsConfig{tendermintHeaders: map[string]string{"X-Api-Key": "secret"}}
+	client, err := creds.dialEvents(ctx, addr, slog.Default())
+	if err != nil {
+		t.Fatalf("dialEvents failed: %v", err)
+	}
+	events, err := client.Subscribe(ctx, query, 1)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if block, ok := ev.Data.(tmtypes.EventDataNewBlock); !ok || block.Block.Height != 7 {
+			t.Errorf("event = %v, want block 7", ev.Data)
+		}
+	case <-ctx.Done():
+		t.Fatalf("no event received")
+	}
+	if _, err := client.Subscribe(ctx, "tm.event='Tx'", 1); err == nil || !strings.Contains(err.Error(), "max_subscriptions_per_client") {
+		t.Errorf("Subscribe() error = %v, want the error of the node", err)
+	}
+
+	client.Stop()
+	if client.IsRunning() {
+		t.Errorf("client must not be running once stopped")
+	}
+}
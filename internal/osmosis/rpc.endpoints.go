@@ -0,0 +1,183 @@
+package osmosis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// endpointEWMAWeight is the weight of the latest observation in latency and error rate moving averages.
+	endpointEWMAWeight = 0.2
+	// endpointUnhealthyErrorRate is the error rate at which the endpoint is considered unhealthy.
+	endpointUnhealthyErrorRate = 0.5
+	endpointProbePeriod        = time.Second * 30
+)
+
+// endpoint is a single node connection with health statistics.
+type endpoint[T any] struct {
+	url    string
+	client T
+
+	mu        sync.Mutex
+	latency   time.Duration
+	errorRate float64
+}
+
+func (e *endpoint[T]) observe(latency time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	failure := float64(0)
+	if err != nil {
+		failure = 1
+	} else if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(float64(e.latency)*(1-endpointEWMAWeight) + float64(latency)*endpointEWMAWeight)
+	}
+	e.errorRate = e.errorRate*(1-endpointEWMAWeight) + failure*endpointEWMAWeight
+}
+
+// score ranks endpoints by latency penalized by error rate. Lower is better.
+func (e *endpoint[T]) score() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return float64(e.latency+time.Millisecond) * (1 + 10*e.errorRate)
+}
+
+func (e *endpoint[T]) healthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.errorRate < endpointUnhealthyErrorRate
+}
+
+// endpointPool is a list of interchangeable node connections.
+type endpointPool[T any] []*endpoint[T]
+
+func newEndpointPool[T any](urls []string, dial func(url string) (T, error)) (endpointPool[T], error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no endpoints configured")
+	}
+
+	pool := make(endpointPool[T], len(urls))
+	for i, url := range urls {
+		client, err := dial(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed connecting to %s: %w", url, err)
+		}
+		pool[i] = &endpoint[T]{
+			url:    url,
+			client: client,
+		}
+	}
+	return pool, nil
+}
+
+// ordered returns endpoints sorted from the best to the worst.
+func (p endpointPool[T]) ordered() []*endpoint[T] {
+	scores := make(map[*endpoint[T]]float64, len(p))
+	for _, e := range p {
+		scores[e] = e.score()
+	}
+
+	endpoints := make([]*endpoint[T], len(p))
+	copy(endpoints, p)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return scores[endpoints[i]] < scores[endpoints[j]]
+	})
+	return endpoints
+}
+
+func (p endpointPool[T]) best() *endpoint[T] {
+	return p.ordered()[0]
+}
+
+func (p endpointPool[T]) status() string {
+	healthy := 0
+	for _, e := range p {
+		if e.healthy() {
+			healthy++
+		}
+	}
+	return fmt.Sprintf("%d/%d", healthy, len(p))
+}
+
+// probe calls check on every endpoint so that failed endpoints can recover their score.
+func (p endpointPool[T]) probe(check func(client T) error) {
+	for _, e := range p {
+		now := time.Now()
+		err := check(e.client)
+		e.observe(time.Since(now), err)
+	}
+}
+
+// failover calls the endpoints starting from the healthiest one until the call succeeds
+// or fails with an error that is not caused by the endpoint.
+func failover[T, R any](p endpointPool[T], call func(client T) (R, error)) (R, error) {
+	var errArr []error
+	for _, e := range p.ordered() {
+		now := time.Now()
+		res, err := call(e.client)
+		if !isEndpointFailure(err) {
+			e.observe(time.Since(now), nil)
+			return res, err
+		}
+		e.observe(time.Since(now), err)
+		errArr = append(errArr, fmt.Errorf("%s: %w", e.url, err))
+	}
+
+	var zero R
+	return zero, errors.Join(errArr...)
+}
+
+// isEndpointFailure reports whether err indicates a problem with the node rather than the request:
+// a transport error, a timeout, a response that is not JSON, e.g. an error page of a proxy,
+// or an Unavailable or DeadlineExceeded gRPC status. Application errors, e.g. a height that
+// is not available, do not count against the node.
+func isEndpointFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr) ||
+		errors.As(err, &syntaxErr)
+}
+
+// monitorEndpoints periodically probes all the endpoints.
+func (c *rpc) monitorEndpoints() error {
+	ticker := time.NewTicker(endpointProbePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.logger.Info("rpc.monitorEndpoints: Context Done")
+			return nil
+		case <-ticker.C:
+		}
+
+		c.grpcs.probe(c.probeGRPC)
+		c.archives.probe(c.probeGRPC)
+		c.tendermints.probe(c.probeTendermint)
+	}
+}
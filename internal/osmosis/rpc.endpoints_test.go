@@ -0,0 +1,144 @@
+package osmosis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFailover(t *testing.T) {
+	tests := []struct {
+		name      string
+		results   map[string]error
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			"first healthy",
+			map[string]error{"a": nil, "b": nil},
+			[]string{"a"},
+			false,
+		},
+		{
+			"fail over",
+			map[string]error{"a": status.Error(codes.Unavailable, "down"), "b": nil},
+			[]string{"a", "b"},
+			false,
+		},
+		{
+			"request error",
+			map[string]error{"a": status.Error(codes.NotFound, "no pool"), "b": nil},
+			[]string{"a"},
+			true,
+		},
+		{
+			"canceled",
+			map[string]error{"a": context.Canceled, "b": nil},
+			[]string{"a"},
+			true,
+		},
+		{
+			"application error",
+			map[string]error{"a": errors.New("height 5 is not available, lowest height is 10"), "b": nil},
+			[]string{"a"},
+			true,
+		},
+		{
+			"all failed",
+			map[string]error{"a": errRefused, "b": errRefused},
+			[]string{"a", "b"},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := newEndpointPool([]string{"a", "b"}, func(url string) (string, error) { return url, nil })
+			if err != nil {
+				t.Fatalf("newEndpointPool failed: %v", err)
+			}
+
+			var calls []string
+			_, err = failover(pool, func(client string) (struct{}, error) {
+				calls = append(calls, client)
+				return struct{}{}, tt.results[client]
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("failover() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("failover() calls = %v, want %v", calls, tt.wantCalls)
+			}
+			for i := range calls {
+				if calls[i] != tt.wantCalls[i] {
+					t.Errorf("failover() calls = %v, want %v", calls, tt.wantCalls)
+				}
+			}
+		})
+	}
+}
+
+var errRefused = fmt.Errorf("post failed: %w", &url.Error{Op: "Post", URL: "http://a", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}})
+
+func Test_isEndpointFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"timeout", fmt.Errorf("post failed: %w", context.DeadlineExceeded), true},
+		{"refused", errRefused, true},
+		{"eof", fmt.Errorf("post failed: %w", io.EOF), true},
+		{"proxy error page", fmt.Errorf("error unmarshalling: %w", json.Unmarshal([]byte("<html>"), &struct{}{})), true},
+		{"rpc error", &rpctypes.RPCError{Code: -32603, Message: "Internal error", Data: "height 5 is not available, lowest height is 10"}, false},
+		{"plain error", errors.New("pool not found"), false},
+		{"grpc unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"grpc deadline", status.Error(codes.DeadlineExceeded, "slow"), true},
+		{"grpc not found", status.Error(codes.NotFound, "no pool"), false},
+		{"grpc invalid argument", status.Error(codes.InvalidArgument, "bad height"), false},
+		{"grpc internal", status.Error(codes.Internal, "query failed"), false},
+	}
+	for _, tt := range tests {
+		if got := isEndpointFailure(tt.err); got != tt.want {
+			t.Errorf("isEndpointFailure(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointPool_ordered(t *testing.T) {
+	pool, err := newEndpointPool([]string{"a", "b"}, func(url string) (string, error) { return url, nil })
+	if err != nil {
+		t.Fatalf("newEndpointPool failed: %v", err)
+	}
+
+	if got := pool.best().url; got != "a" {
+		t.Errorf("best() = %s, want a", got)
+	}
+
+	pool[0].observe(0, errors.New("down"))
+	if got := pool.best().url; got != "b" {
+		t.Errorf("best() after failure = %s, want b", got)
+	}
+	if got := pool.status(); got != "2/2" {
+		t.Errorf("status() = %s, want 2/2", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		pool[0].observe(0, errors.New("down"))
+	}
+	if got := pool.status(); got != "1/2" {
+		t.Errorf("status() = %s, want 1/2", got)
+	}
+}
@@ -0,0 +1,273 @@
+package osmosis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	websocketHandshakeTimeout = time.Second * 10
+	websocketWriteTimeout     = time.Second * 10
+	websocketCallTimeout      = time.Second * 10
+)
+
+var errEventsClosed = errors.New("websocket is closed")
+
+// eventsClient subscribes to Tendermint events over the websocket. Unlike the cometbft client,
+// it sends the configured headers and basic auth with the websocket handshake.
+type eventsClient struct {
+	conn   *websocket.Conn
+	logger *slog.Logger
+	nextID atomic.Int64
+
+	writeMu       sync.Mutex
+	mu            sync.RWMutex
+	subscriptions map[string]chan ctypes.ResultEvent
+	pending       map[rpctypes.JSONRPCIntID]chan error
+
+	stopOnce sync.Once
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+// dialEvents connects to the websocket of the Tendermint RPC at addr. Basic auth may also be set in the address.
+func (c credentialsConfig) dialEvents(ctx context.Context, addr string, logger *slog.Logger) (*eventsClient, error) {
+	u, err := websocketURL(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &http.Request{Header: make(http.Header)}
+	for k, v := range c.tendermintHeaders {
+		req.Header.Set(k, v)
+	}
+	if c.tendermintUser != "" {
+		req.SetBasicAuth(c.tendermintUser, c.tendermintPassword)
+	} else if u.User != nil {
+		password, _ := u.User.Password()
+		req.SetBasicAuth(u.User.Username(), password)
+	}
+	u.User = nil
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: websocketHandshakeTimeout,
+	}
+	conn, resp, err := dialer.DialContext(ctx, u.String(), req.Header)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("websocket handshake failed with %s: %w", resp.Status, err)
+		}
+		return nil, err
+	}
+
+	e := &eventsClient{
+		conn:          conn,
+		logger:        logger,
+		subscriptions: make(map[string]chan ctypes.ResultEvent),
+		pending:       make(map[rpctypes.JSONRPCIntID]chan error),
+		stopping:      make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go e.readEvents()
+	return e, nil
+}
+
+// websocketURL returns the websocket endpoint of a Tendermint RPC address, e.g. tcp://host:26657.
+func websocketURL(addr string) (*url.URL, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "tcp://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "tcp", "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return nil, fmt.Errorf("unsupported Tendermint address %q", addr)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/websocket"
+	return u, nil
+}
+
+// Subscribe subscribes to query. Events are delivered on the returned channel, which is never closed.
+func (e *eventsClient) Subscribe(ctx context.Context, query string, capacity int) (<-chan ctypes.ResultEvent, error) {
+	out := make(chan ctypes.ResultEvent, capacity)
+	e.mu.Lock()
+	e.subscriptions[query] = out
+	e.mu.Unlock()
+
+	if err := e.call(ctx, "subscribe", map[string]any{"query": query}); err != nil {
+		e.mu.Lock()
+		delete(e.subscriptions, query)
+		e.mu.Unlock()
+		return nil, err
+	}
+	return out, nil
+}
+
+func (e *eventsClient) UnsubscribeAll(ctx context.Context) error {
+	if err := e.call(ctx, "unsubscribe_all", map[string]any{}); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.subscriptions = make(map[string]chan ctypes.ResultEvent)
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *eventsClient) IsRunning() bool {
+	select {
+	case <-e.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Stop closes the connection and waits until no more events are delivered.
+func (e *eventsClient) Stop() error {
+	var err error
+	e.stopOnce.Do(func() {
+		close(e.stopping)
+		err = e.conn.Close()
+	})
+	<-e.done
+	return err
+}
+
+// call sends the request and waits for the response. Errors returned by the node are returned as *rpctypes.RPCError.
+func (e *eventsClient) call(ctx context.Context, method string, params map[string]any) error {
+	if !e.IsRunning() {
+		return errEventsClosed
+	}
+	id := rpctypes.JSONRPCIntID(e.nextID.Add(1))
+	request, err := rpctypes.MapToRequest(id, method, params)
+	if err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, websocketCallTimeout)
+		defer cancel()
+	}
+
+	reply := make(chan error, 1)
+	e.mu.Lock()
+	e.pending[id] = reply
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		delete(e.pending, id)
+		e.mu.Unlock()
+	}()
+
+	if err := e.write(ctx, request); err != nil {
+		return err
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-e.done:
+		return errEventsClosed
+	case <-ctx.Done():
+		return fmt.Errorf("no response to %s: %w", method, ctx.Err())
+	}
+}
+
+func (e *eventsClient) write(ctx context.Context, request rpctypes.RPCRequest) error {
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+	deadline, _ := ctx.Deadline()
+	e.conn.SetWriteDeadline(deadline)
+	return e.conn.WriteJSON(request)
+}
+
+// reply delivers the response to the pending request with the same id.
+// Events carry the id of the subscribe request with an "#event" suffix, so they are never taken as responses.
+func (e *eventsClient) reply(resp *rpctypes.RPCResponse) bool {
+	id, ok := resp.ID.(rpctypes.JSONRPCIntID)
+	if !ok {
+		return false
+	}
+	e.mu.Lock()
+	reply, ok := e.pending[id]
+	delete(e.pending, id)
+	e.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if resp.Error != nil {
+		reply <- resp.Error
+	} else {
+		reply <- nil
+	}
+	return true
+}
+
+func (e *eventsClient) readEvents() {
+	defer close(e.done)
+	for {
+		_, data, err := e.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-e.stopping:
+			default:
+				e.logger.Warn("Tendermint websocket closed", "err", err)
+				e.conn.Close()
+			}
+			return
+		}
+
+		var resp rpctypes.RPCResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			e.logger.Error("Failed to unmarshal websocket response", "err", err)
+			continue
+		}
+		if e.reply(&resp) {
+			continue
+		}
+		if resp.Error != nil {
+			e.logger.Error("Tendermint websocket error", "err", resp.Error)
+			continue
+		}
+		var event ctypes.ResultEvent
+		if err := cmtjson.Unmarshal(resp.Result, &event); err != nil {
+			e.logger.Error("Failed to unmarshal websocket event", "err", err)
+			continue
+		}
+
+		e.mu.RLock()
+		out, ok := e.subscriptions[event.Query]
+		e.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		select {
+		case out <- event:
+		case <-e.stopping:
+			return
+		}
+	}
+}
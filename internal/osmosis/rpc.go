@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/synternet/osmosis-publisher/pkg/types"
 
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 
 	IBCTypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
@@ -36,29 +38,43 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
-const subscriberName = "dlosmpub"
-
 type rpc struct {
-	ctx           context.Context
-	group         *errgroup.Group
-	cancel        context.CancelCauseFunc
-	db            repository.Repository
-	logger        *slog.Logger
-	tendermintUrl string
-	grpcApiURL    string
-	tendermint    *rpchttp.HTTP
-	grpc          *grpc.ClientConn
-	mempoolSet    map[string]struct{}
-	enccfg        params.EncodingConfig
-
-	pmQueryClient  queryproto.QueryClient
-	ibcQueryClient IBCTypes.QueryClient
-
-	errCounter     atomic.Uint64
-	evtCounter     atomic.Uint64
-	evtSkipCounter atomic.Uint64
-	queueMaxSize   atomic.Uint64
-	maxQueueSize   uint64
+	ctx          context.Context
+	group        *errgroup.Group
+	cancel       context.CancelCauseFunc
+	db           repository.Repository
+	logger       *slog.Logger
+	tendermints  endpointPool[*rpchttp.HTTP]
+	grpcs        endpointPool[*grpcClient]
+	archives     endpointPool[*grpcClient]
+	archiveDepth uint64
+	latestHeight atomic.Uint64
+	concurrency  int
+	limiter      *rate.Limiter
+	credentials  credentialsConfig
+	websocket    atomic.Pointer[eventsClient]
+	mempoolSet   map[string]struct{}
+	enccfg       params.EncodingConfig
+
+	errCounter      atomic.Uint64
+	evtCounter      atomic.Uint64
+	evtSkipCounter  atomic.Uint64
+	evtSpillCounter atomic.Uint64
+	spillMaxSize    atomic.Uint64
+	spillDir        string
+	spillMaxBytes   int64
+	queueMaxSize    atomic.Uint64
+	maxQueueSize    uint64
+
+	subscriptionsMu  sync.Mutex
+	subscriptions    []*subscription
+	wsEndpoint       *endpoint[*rpchttp.HTTP]
+	connCtx          context.Context
+	connCancel       context.CancelFunc
+	lastEvent        atomic.Int64
+	reconnectCounter atomic.Uint64
+	downtime         atomic.Int64
+	archiveCounter   atomic.Uint64
 
 	mempoolHist       prometheus.Histogram
 	poolAllHist       prometheus.Histogram
@@ -69,6 +85,13 @@ type rpc struct {
 	getDenoms func(ibcTrace IBCDenomTrace) error
 }
 
+// grpcClient holds gRPC query clients of a single node.
+type grpcClient struct {
+	conn *grpc.ClientConn
+	pm   queryproto.QueryClient
+	ibc  IBCTypes.QueryClient
+}
+
 // Will add Height to gRPC call context. This will instruct the full node to return the state at that height.
 // NOTE: It will ignore height values <= 0
 func ContextWithHeight(ctx context.Context, height int64) context.Context {
@@ -82,18 +105,35 @@ func ContextWithHeight(ctx context.Context, height int64) context.Context {
 	)
 }
 
-func newRpc(ctx context.Context, cancel context.CancelCauseFunc, group *errgroup.Group, logger *slog.Logger, db repository.Repository, getDenoms func(ibcTrace IBCDenomTrace) error, tendermintUrl, grpcApiURL string) (*rpc, error) {
+// rpcConfig holds the node endpoints and query limits of the RPC.
+type rpcConfig struct {
+	tendermintURLs   []string
+	grpcURLs         []string
+	archiveURLs      []string
+	archiveDepth     uint64
+	concurrency      int
+	queriesPerSecond float64
+	credentials      credentialsConfig
+	spillDir         string
+	spillMaxBytes    int64
+}
+
+func newRpc(ctx context.Context, cancel context.CancelCauseFunc, group *errgroup.Group, logger *slog.Logger, db repository.Repository, getDenoms func(ibcTrace IBCDenomTrace) error, cfg rpcConfig) (*rpc, error) {
 	ret := &rpc{
 		ctx:           ctx,
 		group:         group,
 		cancel:        cancel,
 		logger:        logger.With("module", "rpc"),
-		tendermintUrl: tendermintUrl,
-		grpcApiURL:    grpcApiURL,
 		mempoolSet:    make(map[string]struct{}),
 		db:            db,
 		enccfg:        app.MakeEncodingConfig(),
 		getDenoms:     getDenoms,
+		archiveDepth:  cfg.archiveDepth,
+		concurrency:   cfg.concurrency,
+		limiter:       newRateLimiter(cfg.queriesPerSecond, cfg.concurrency),
+		credentials:   cfg.credentials,
+		spillDir:      cfg.spillDir,
+		spillMaxBytes: cfg.spillMaxBytes,
 
 		mempoolHist: prometheus.NewHistogram(
 			prometheus.HistogramOpts{
@@ -127,32 +167,50 @@ func newRpc(ctx context.Context, cancel context.CancelCauseFunc, group *errgroup
 		),
 	}
 
-	logger.Info("Using RPC", "tendermint", tendermintUrl, "gRPC", grpcApiURL)
+	logger.Info("Using RPC", "tendermint", cfg.tendermintURLs, "gRPC", cfg.grpcURLs)
 
-	grpcConn, err := grpc.Dial(
-		grpcApiURL,
-		grpc.WithInsecure(),
-	)
+	grpcOpts, err := cfg.credentials.grpcDialOptions()
 	if err != nil {
 		return nil, err
 	}
-	ret.grpc = grpcConn
+	dial := func(url string) (*grpcClient, error) {
+		return dialGRPC(url, grpcOpts...)
+	}
+
+	grpcs, err := newEndpointPool(cfg.grpcURLs, dial)
+	if err != nil {
+		return nil, err
+	}
+	ret.grpcs = grpcs
+
+	if len(cfg.archiveURLs) > 0 {
+		logger.Info("Using archive gRPC", "gRPC", cfg.archiveURLs, "depth", cfg.archiveDepth)
+		archives, err := newEndpointPool(cfg.archiveURLs, dial)
+		if err != nil {
+			return nil, err
+		}
+		ret.archives = archives
+	}
 
 	tmlog.AllowAll()
 
-	client, err := rpchttp.NewWithTimeout(tendermintUrl, "/websocket", 3)
+	tendermints, err := newEndpointPool(cfg.tendermintURLs, cfg.credentials.newTendermintClient)
 	if err != nil {
 		return nil, err
 	}
+	ret.tendermints = tendermints
 
-	err = client.Start()
+	ret.wsEndpoint = tendermints.best()
+	client, err := ret.dialWebsocket(ret.wsEndpoint.url)
 	if err != nil {
 		return nil, err
 	}
-	ret.tendermint = client
+	ret.websocket.Store(client)
+	ret.connCtx, ret.connCancel = context.WithCancel(ctx)
+	ret.lastEvent.Store(time.Now().UnixNano())
 
-	ret.pmQueryClient = queryproto.NewQueryClient(ret.grpc)
-	ret.ibcQueryClient = IBCTypes.NewQueryClient(ret.grpc)
+	group.Go(ret.monitorConnection)
+	group.Go(ret.monitorEndpoints)
 
 	return ret, nil
 }
@@ -161,13 +219,16 @@ func (c *rpc) Close() error {
 	c.logger.Info("Publisher.RPC.Close")
 	c.cancel(nil)
 	var errArr []error
-	if c.tendermint != nil {
+	if tendermint := c.websocket.Load(); tendermint != nil {
 		c.logger.Info("Publisher.RPC.UnsubscribeAll")
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 		defer cancel()
-		errArr = append(errArr, c.tendermint.UnsubscribeAll(ctx, subscriberName))
+		errArr = append(errArr, tendermint.UnsubscribeAll(ctx))
 		c.logger.Info("Publisher.RPC.tendermint.Stop")
-		errArr = append(errArr, c.tendermint.Stop())
+		errArr = append(errArr, tendermint.Stop())
+	}
+	for _, e := range append(c.grpcs, c.archives...) {
+		errArr = append(errArr, e.client.conn.Close())
 	}
 	c.logger.Info("Publisher.RPC.group.Wait")
 	errGr := c.group.Wait()
@@ -179,15 +240,42 @@ func (c *rpc) Close() error {
 	return err
 }
 
-func (c *rpc) DenomTrace(ibc string) (IBCTypes.DenomTrace, error) {
+func dialGRPC(url string, opts ...grpc.DialOption) (*grpcClient, error) {
+	conn, err := grpc.Dial(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcClient{
+		conn: conn,
+		pm:   queryproto.NewQueryClient(conn),
+		ibc:  IBCTypes.NewQueryClient(conn),
+	}, nil
+}
+
+func (c *rpc) probeGRPC(client *grpcClient) error {
+	ctx, cancel := context.WithTimeout(c.ctx, time.Second)
+	defer cancel()
+	_, err := client.pm.NumPools(ctx, &queryproto.NumPoolsRequest{})
+	return err
+}
+
+func (c *rpc) probeTendermint(client *rpchttp.HTTP) error {
 	ctx, cancel := context.WithTimeout(c.ctx, time.Second)
 	defer cancel()
+	_, err := client.Health(ctx)
+	return err
+}
 
+func (c *rpc) DenomTrace(ibc string) (IBCTypes.DenomTrace, error) {
 	req := &IBCTypes.QueryDenomTraceRequest{
 		Hash: ibc,
 	}
 
-	res, err := c.ibcQueryClient.DenomTrace(ctx, req)
+	res, err := failover(c.grpcs, func(client *grpcClient) (*IBCTypes.QueryDenomTraceResponse, error) {
+		ctx, cancel := context.WithTimeout(c.ctx, time.Second)
+		defer cancel()
+		return client.ibc.DenomTrace(ctx, req)
+	})
 	if err != nil {
 		return IBCTypes.DenomTrace{}, err
 	}
@@ -206,10 +294,12 @@ func (c *rpc) DenomTraces() ([]IBCTypes.DenomTrace, error) {
 				Limit: 100, // Adjust the limit as necessary
 			},
 		}
-		ctx, cancel := context.WithTimeout(c.ctx, time.Second)
 		now := time.Now()
-		res, err := c.ibcQueryClient.DenomTraces(ctx, req)
-		cancel()
+		res, err := failover(c.grpcs, func(client *grpcClient) (*IBCTypes.QueryDenomTracesResponse, error) {
+			ctx, cancel := context.WithTimeout(c.ctx, time.Second)
+			defer cancel()
+			return client.ibc.DenomTraces(ctx, req)
+		})
 		if err != nil {
 			c.errCounter.Add(1)
 			c.logger.Error("Failed to fetch denom traces", "err", err)
@@ -228,19 +318,46 @@ func (c *rpc) DenomTraces() ([]IBCTypes.DenomTrace, error) {
 	return traces, nil
 }
 
+// BlockAt returns the block at the given height.
+// NOTE: Will return the latest block for height values <= 0
 func (c *rpc) BlockAt(height int64) (*tmtypes.Block, error) {
-	ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
-	ctx = ContextWithHeight(ctx, height)
-	defer cancel()
-	info, err := c.tendermint.Block(ctx, nil)
+	info, err := failover(c.tendermints, func(client *rpchttp.HTTP) (*ctypes.ResultBlock, error) {
+		ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
+		defer cancel()
+		return client.Block(ctx, heightOrLatest(height))
+	})
 	if err != nil {
 		c.errCounter.Add(1)
 		return nil, err
 	}
+	setMaxValue(&c.latestHeight, uint64(info.Block.Height))
 
 	return info.Block, nil
 }
 
+// BlockResultsAt returns transaction results and events of the block at the given height.
+// NOTE: Will return the results of the latest block for height values <= 0
+func (c *rpc) BlockResultsAt(height int64) (*ctypes.ResultBlockResults, error) {
+	res, err := failover(c.tendermints, func(client *rpchttp.HTTP) (*ctypes.ResultBlockResults, error) {
+		ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
+		defer cancel()
+		return client.BlockResults(ctx, heightOrLatest(height))
+	})
+	if err != nil {
+		c.errCounter.Add(1)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func heightOrLatest(height int64) *int64 {
+	if height <= 0 {
+		return nil
+	}
+	return &height
+}
+
 func (c *rpc) ChainID() (string, error) {
 	block, err := c.BlockAt(0)
 	if err != nil {
@@ -261,25 +378,51 @@ func (c *rpc) LatestBlockHeight() (int64, error) {
 	return block.Height, nil
 }
 
+// Subscribe registers a websocket subscription for eventName and runs handle with a channel of events.
+// The channel survives reconnects: the query is re-issued on every new connection and its
+// events keep flowing into the same channel. Handlers of the same eventName share a single subscription.
 func (c *rpc) Subscribe(eventName string, handle func(events <-chan ctypes.ResultEvent) error) error {
-	ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
-	events, err := c.tendermint.Subscribe(ctx, subscriberName, eventName, 10)
-	cancel()
-	if err != nil {
-		return err
+	consumer := make(chan ctypes.ResultEvent, 10)
+
+	c.subscriptionsMu.Lock()
+	var sub *subscription
+	for _, s := range c.subscriptions {
+		if s.query == eventName {
+			sub = s
+			break
+		}
 	}
+	if sub == nil {
+		sub = &subscription{
+			query:  eventName,
+			events: make(chan ctypes.ResultEvent, 10),
+		}
+		if err := c.subscribe(c.connCtx, c.websocket.Load(), sub); err != nil {
+			c.subscriptionsMu.Unlock()
+			return err
+		}
+		c.subscriptions = append(c.subscriptions, sub)
+		c.group.Go(func() error {
+			return sub.fanOut(c.ctx)
+		})
+	}
+	sub.addConsumer(consumer)
+	c.subscriptionsMu.Unlock()
+
 	c.group.Go(func() error {
-		return handle(c.bufferChannel(eventName, events, 2048))
+		return handle(c.bufferChannel(eventName, consumer, 2048))
 	})
 	return nil
 }
 
 func (c *rpc) Mempool() ([]*types.Transaction, error) {
 	var limit int = 1000
-	ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
-	defer cancel()
 	now := time.Now()
-	res, err := c.tendermint.UnconfirmedTxs(ctx, &limit)
+	res, err := failover(c.tendermints, func(client *rpchttp.HTTP) (*ctypes.ResultUnconfirmedTxs, error) {
+		ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
+		defer cancel()
+		return client.UnconfirmedTxs(ctx, &limit)
+	})
 	if err != nil {
 		c.errCounter.Add(1)
 		return nil, err
@@ -322,13 +465,16 @@ func (c *rpc) Mempool() ([]*types.Transaction, error) {
 	return txs, nil
 }
 
+// PoolsAt returns pool states at height. All the pools are returned if ids is nil.
+// Pools are fetched concurrently; the pools that failed are omitted from the result and reported as PoolError.
 func (c *rpc) PoolsAt(height int64, ids ...uint64) ([]*pmtypes.PoolI, error) {
 	if ids == nil {
-		ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
-		ctx = ContextWithHeight(ctx, height)
-		defer cancel()
 		now := time.Now()
-		resp, err := c.pmQueryClient.AllPools(ctx, &queryproto.AllPoolsRequest{})
+		resp, err := stateQuery(c, height, func(client *grpcClient) (*queryproto.AllPoolsResponse, error) {
+			ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
+			defer cancel()
+			return client.pm.AllPools(ContextWithHeight(ctx, height), &queryproto.AllPoolsRequest{})
+		})
 		if err != nil {
 			c.errCounter.Add(1)
 			return nil, fmt.Errorf("failed retrieving all pools: %w", err)
@@ -337,66 +483,86 @@ func (c *rpc) PoolsAt(height int64, ids ...uint64) ([]*pmtypes.PoolI, error) {
 		return c.translatePools(resp.Pools)
 	}
 
-	pools := make([]*types1.Any, len(ids))
-	for i, id := range ids {
-		ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
-		ctx = ContextWithHeight(ctx, height)
+	return forEachPool(c, ids, func(id uint64) (*pmtypes.PoolI, error) {
 		now := time.Now()
-		resp, err := c.pmQueryClient.Pool(ctx, &queryproto.PoolRequest{PoolId: id})
-		cancel()
+		resp, err := stateQuery(c, height, func(client *grpcClient) (*queryproto.PoolResponse, error) {
+			ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
+			defer cancel()
+			return client.pm.Pool(ContextWithHeight(ctx, height), &queryproto.PoolRequest{PoolId: id})
+		})
 		if err != nil {
 			c.errCounter.Add(1)
 			return nil, fmt.Errorf("failed retrieving pool %d: %w", id, err)
 		}
 		c.poolAllHist.Observe(time.Since(now).Seconds())
-		pools[i] = resp.Pool
-	}
 
-	return c.translatePools(pools)
+		pools, err := c.translatePools([]*types1.Any{resp.Pool})
+		if err != nil {
+			return nil, fmt.Errorf("failed translating pool %d: %w", id, err)
+		}
+		return pools[0], nil
+	})
 }
 
-func (c *rpc) PoolsTotalLiquidityAt(height int64, ids ...uint64) ([]types.PoolLiquidity, error) {
-	pools := make([]types.PoolLiquidity, len(ids))
-	for i, id := range ids {
+// HasStateAt reports whether the state at height can be queried either from the primary or the archive endpoints.
+func (c *rpc) HasStateAt(height int64) (bool, error) {
+	_, err := stateQuery(c, height, func(client *grpcClient) (*queryproto.NumPoolsResponse, error) {
 		ctx, cancel := context.WithTimeout(c.ctx, time.Second)
-		ctx = ContextWithHeight(ctx, height)
+		defer cancel()
+		return client.pm.NumPools(ContextWithHeight(ctx, height), &queryproto.NumPoolsRequest{})
+	})
+	if err == nil {
+		return true, nil
+	}
+	if isPrunedHeightError(err) {
+		return false, nil
+	}
+	c.errCounter.Add(1)
+	return false, err
+}
+
+// PoolsTotalLiquidityAt returns total liquidity of pools at height.
+// Pools are fetched concurrently; the pools that failed are omitted from the result and reported as PoolError.
+func (c *rpc) PoolsTotalLiquidityAt(height int64, ids ...uint64) ([]types.PoolLiquidity, error) {
+	return forEachPool(c, ids, func(id uint64) (types.PoolLiquidity, error) {
 		now := time.Now()
-		resp, err := c.pmQueryClient.TotalPoolLiquidity(ctx, &queryproto.TotalPoolLiquidityRequest{PoolId: id})
-		cancel()
+		resp, err := stateQuery(c, height, func(client *grpcClient) (*queryproto.TotalPoolLiquidityResponse, error) {
+			ctx, cancel := context.WithTimeout(c.ctx, time.Second)
+			defer cancel()
+			return client.pm.TotalPoolLiquidity(ContextWithHeight(ctx, height), &queryproto.TotalPoolLiquidityRequest{PoolId: id})
+		})
 		if err != nil {
 			c.errCounter.Add(1)
-			return nil, fmt.Errorf("failed retrieving pool liquidity %d: %w", id, err)
+			return types.PoolLiquidity{}, fmt.Errorf("failed retrieving pool liquidity %d: %w", id, err)
 		}
 		c.poolLiquidityHist.Observe(time.Since(now).Seconds())
-		pools[i] = types.PoolLiquidity{
+		return types.PoolLiquidity{
 			PoolId:    id,
 			Liquidity: resp.Liquidity,
-		}
-	}
-
-	return pools, nil
+		}, nil
+	})
 }
 
+// PoolsVolumeAt returns total volume of pools at height.
+// Pools are fetched concurrently; the pools that failed are omitted from the result and reported as PoolError.
 func (c *rpc) PoolsVolumeAt(height int64, ids ...uint64) ([]types.PoolVolume, error) {
-	pools := make([]types.PoolVolume, len(ids))
-	for i, id := range ids {
-		ctx, cancel := context.WithTimeout(c.ctx, time.Second)
-		ctx = ContextWithHeight(ctx, height)
+	return forEachPool(c, ids, func(id uint64) (types.PoolVolume, error) {
 		now := time.Now()
-		resp, err := c.pmQueryClient.TotalVolumeForPool(ctx, &queryproto.TotalVolumeForPoolRequest{PoolId: id})
-		cancel()
+		resp, err := stateQuery(c, height, func(client *grpcClient) (*queryproto.TotalVolumeForPoolResponse, error) {
+			ctx, cancel := context.WithTimeout(c.ctx, time.Second)
+			defer cancel()
+			return client.pm.TotalVolumeForPool(ContextWithHeight(ctx, height), &queryproto.TotalVolumeForPoolRequest{PoolId: id})
+		})
 		if err != nil {
 			c.errCounter.Add(1)
-			return nil, fmt.Errorf("failed retrieving pool volume %d: %w", id, err)
+			return types.PoolVolume{}, fmt.Errorf("failed retrieving pool volume %d: %w", id, err)
 		}
 		c.poolVolumeHist.Observe(time.Since(now).Seconds())
-		pools[i] = types.PoolVolume{
+		return types.PoolVolume{
 			PoolId: id,
 			Volume: resp.Volume,
-		}
-	}
-
-	return pools, nil
+		}, nil
+	})
 }
 
 func (p *rpc) getStatus() map[string]string {
@@ -406,24 +572,62 @@ func (p *rpc) getStatus() map[string]string {
 	}
 
 	return map[string]string{
-		"errors":           strconv.FormatUint(p.errCounter.Swap(0), 10),
-		"events_total":     strconv.FormatUint(p.evtCounter.Swap(0), 10),
-		"events_skipped":   strconv.FormatUint(p.evtSkipCounter.Load(), 10),
-		"events_queue":     strconv.FormatUint(queueSize, 10),
-		"events_max_queue": strconv.FormatUint(p.maxQueueSize, 10),
+		"errors":               strconv.FormatUint(p.errCounter.Swap(0), 10),
+		"events_total":         strconv.FormatUint(p.evtCounter.Swap(0), 10),
+		"events_skipped":       strconv.FormatUint(p.evtSkipCounter.Load(), 10),
+		"events_queue":         strconv.FormatUint(queueSize, 10),
+		"events_max_queue":     strconv.FormatUint(p.maxQueueSize, 10),
+		"events_spilled":       strconv.FormatUint(p.evtSpillCounter.Swap(0), 10),
+		"events_spill_bytes":   strconv.FormatUint(p.spillMaxSize.Swap(0), 10),
+		"websocket_reconnects": strconv.FormatUint(p.reconnectCounter.Load(), 10),
+		"websocket_downtime":   time.Duration(p.downtime.Load()).String(),
+		"websocket_endpoint":   p.websocketURL(),
+		"grpc_healthy":         p.grpcs.status(),
+		"tendermint_healthy":   p.tendermints.status(),
+		"archive_queries":      strconv.FormatUint(p.archiveCounter.Swap(0), 10),
 	}
 }
 
+// bufferChannel forwards events into a channel of the given size. When the channel is full,
+// events are spilled to disk and drained back in order. Events are dropped only if spilling
+// is disabled or its budget is exhausted.
 func (c *rpc) bufferChannel(name string, events <-chan ctypes.ResultEvent, size int) <-chan ctypes.ResultEvent {
 	ch := make(chan ctypes.ResultEvent, size)
+
+	var spill *spillQueue
+	if c.spillMaxBytes > 0 {
+		var err error
+		spill, err = newSpillQueue(c.spillDir, c.spillMaxBytes)
+		if err != nil {
+			c.logger.Error("bufferChannel: spilling disabled", "name", name, "err", err)
+		}
+	}
+
 	c.group.Go(func() error {
 		defer close(ch)
 		defer c.logger.Info("bufferChannel exit", "name", name)
+		if spill != nil {
+			defer spill.close()
+		}
+
+		// pending is the oldest spilled event waiting for space in ch.
+		var pending *ctypes.ResultEvent
 		for {
+			if pending == nil && spill != nil && spill.len() > 0 {
+				pending = c.unspill(spill)
+			}
+			var out chan<- ctypes.ResultEvent
+			var next ctypes.ResultEvent
+			if pending != nil {
+				out, next = ch, *pending
+			}
+
 			select {
 			case <-c.ctx.Done():
 				c.logger.Info("bufferChannel: Context Done", "len(events)", len(events))
 				return nil
+			case out <- next:
+				pending = nil
 			case ev, ok := <-events:
 				if !ok {
 					c.logger.Info("bufferChannel: events closed", "len(events)", len(events))
@@ -434,11 +638,17 @@ func (c *rpc) bufferChannel(name string, events <-chan ctypes.ResultEvent, size
 
 				setMaxValue(&c.queueMaxSize, uint64(len(ch)))
 
-				select {
-				case ch <- ev:
-				default:
+				if pending == nil && (spill == nil || spill.len() == 0) {
+					select {
+					case ch <- ev:
+						continue
+					default:
+					}
+				}
+
+				if err := c.spill(spill, ev); err != nil {
 					c.evtSkipCounter.Add(1)
-					c.logger.Info("bufferChannel: Overflow! Skipping", "event", ev.Query)
+					c.logger.Info("bufferChannel: Overflow! Skipping", "event", ev.Query, "err", err)
 				}
 			}
 		}
@@ -97,6 +97,7 @@ func (c *rpc) translateTransaction(
 		Nonce:    nonce,
 		TxID:     txid,
 		Raw:      hex.EncodeToString(txRaw),
+		RawBytes: txRaw,
 		TxResult: txResult,
 	}
 	if code != nil {
@@ -0,0 +1,61 @@
+package osmosis
+
+import (
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+)
+
+// PoolError is reported for every pool that could not be retrieved.
+type PoolError struct {
+	PoolId uint64
+	Err    error
+}
+
+func (e *PoolError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PoolError) Unwrap() error {
+	return e.Err
+}
+
+func newRateLimiter(queriesPerSecond float64, burst int) *rate.Limiter {
+	if queriesPerSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(queriesPerSecond), max(burst, 1))
+}
+
+// forEachPool calls fetch for every pool id with at most c.concurrency calls in flight.
+// Results are returned in the order of ids. Failed pools are omitted and their errors are joined as PoolError.
+func forEachPool[R any](c *rpc, ids []uint64, fetch func(id uint64) (R, error)) ([]R, error) {
+	results := make([]R, len(ids))
+	errs := make([]error, len(ids))
+
+	var group errgroup.Group
+	group.SetLimit(max(c.concurrency, 1))
+	for i, id := range ids {
+		i, id := i, id
+		group.Go(func() error {
+			res, err := fetch(id)
+			if err != nil {
+				errs[i] = &PoolError{PoolId: id, Err: err}
+				return nil
+			}
+			results[i] = res
+			return nil
+		})
+	}
+	group.Wait()
+
+	ret := make([]R, 0, len(ids))
+	for i := range ids {
+		if errs[i] != nil {
+			continue
+		}
+		ret = append(ret, results[i])
+	}
+	return ret, errors.Join(errs...)
+}
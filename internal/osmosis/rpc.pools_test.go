@@ -0,0 +1,51 @@
+package osmosis
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachPool(t *testing.T) {
+	c := &rpc{concurrency: 2}
+
+	var inFlight, maxInFlight atomic.Int32
+	got, err := forEachPool(c, []uint64{1, 2, 3, 4, 5}, func(id uint64) (uint64, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		if id%2 == 0 {
+			return 0, fmt.Errorf("pool %d unavailable", id)
+		}
+		return id * 10, nil
+	})
+
+	if want := []uint64{10, 30, 50}; !reflect.DeepEqual(got, want) {
+		t.Errorf("forEachPool() = %v, want %v", got, want)
+	}
+	if m := maxInFlight.Load(); m > 2 {
+		t.Errorf("forEachPool() ran %d calls concurrently, want at most 2", m)
+	}
+
+	var poolErr *PoolError
+	if !errors.As(err, &poolErr) {
+		t.Fatalf("forEachPool() error = %v, want PoolError", err)
+	}
+	failed := map[uint64]bool{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if errors.As(e, &poolErr) {
+			failed[poolErr.PoolId] = true
+		}
+	}
+	if want := map[uint64]bool{2: true, 4: true}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("forEachPool() failed pools = %v, want %v", failed, want)
+	}
+}
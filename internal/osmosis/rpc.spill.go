@@ -0,0 +1,117 @@
+package osmosis
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/synternet/osmosis-publisher/pkg/recordlog"
+)
+
+var errSpillFull = errors.New("spill queue is full")
+
+// spillQueue is a record log in a temporary file. It is used when the in-memory queue is full.
+// Queued records may take up to maxBytes, see recordlog.Log. The file is removed on close.
+type spillQueue struct {
+	log  *recordlog.Log
+	path string
+}
+
+func newSpillQueue(dir string, maxBytes int64) (*spillQueue, error) {
+	file, err := os.CreateTemp(dir, "spill-*.queue")
+	if err != nil {
+		return nil, fmt.Errorf("failed creating spill file: %w", err)
+	}
+	path := file.Name()
+	file.Close()
+
+	log, err := recordlog.Open(path, "", maxBytes)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed opening spill file: %w", err), os.Remove(path))
+	}
+	return &spillQueue{
+		log:  log,
+		path: path,
+	}, nil
+}
+
+func (q *spillQueue) len() int {
+	return q.log.Len()
+}
+
+// size returns the size of the queued records.
+func (q *spillQueue) size() int64 {
+	return q.log.Size()
+}
+
+// push appends a record. errSpillFull is returned if the record does not fit into the budget.
+func (q *spillQueue) push(record []byte) error {
+	err := q.log.Push(record)
+	if errors.Is(err, recordlog.ErrFull) {
+		return errSpillFull
+	}
+	return err
+}
+
+// pop removes and returns the oldest record. If the file cannot be truncated or compacted,
+// the record is returned together with the error, and the queue must be reset.
+func (q *spillQueue) pop() ([]byte, error) {
+	record, err := q.log.Peek()
+	if err != nil {
+		return nil, err
+	}
+	return record, q.log.Drop()
+}
+
+// reset drops all the records.
+func (q *spillQueue) reset() error {
+	return q.log.Reset()
+}
+
+// close closes and removes the spill file.
+func (q *spillQueue) close() error {
+	return errors.Join(q.log.Close(), os.Remove(q.path))
+}
+
+// spill stores the event in the spill queue.
+func (c *rpc) spill(spill *spillQueue, ev ctypes.ResultEvent) error {
+	if spill == nil {
+		return errSpillFull
+	}
+	record, err := cmtjson.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := spill.push(record); err != nil {
+		return err
+	}
+	c.evtSpillCounter.Add(1)
+	setMaxValue(&c.spillMaxSize, uint64(spill.size()))
+	return nil
+}
+
+// unspill returns the oldest spilled event. Events that cannot be restored are counted as skipped.
+func (c *rpc) unspill(spill *spillQueue) *ctypes.ResultEvent {
+	record, err := spill.pop()
+	if record == nil {
+		c.evtSkipCounter.Add(uint64(spill.len()) + 1)
+		c.logger.Error("bufferChannel: failed reading spilled events", "err", err)
+		spill.reset()
+		return nil
+	}
+	if err != nil {
+		c.evtSkipCounter.Add(uint64(spill.len()))
+		c.logger.Error("bufferChannel: failed truncating spill file, dropping spilled events", "events", spill.len(), "err", err)
+		spill.reset()
+	}
+
+	var ev ctypes.ResultEvent
+	if err := cmtjson.Unmarshal(record, &ev); err != nil {
+		c.evtSkipCounter.Add(1)
+		c.logger.Error("bufferChannel: failed decoding spilled event", "err", err)
+		return nil
+	}
+	return &ev
+}
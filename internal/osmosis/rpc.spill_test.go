@@ -0,0 +1,155 @@
+package osmosis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestSpillQueue(t *testing.T) {
+	q, err := newSpillQueue(t.TempDir(), 20)
+	if err != nil {
+		t.Fatalf("newSpillQueue failed: %v", err)
+	}
+	defer q.close()
+
+	for _, r := range []string{"first", "second"} {
+		if err := q.push([]byte(r)); err != nil {
+			t.Fatalf("push(%s) failed: %v", r, err)
+		}
+	}
+	if err := q.push([]byte("third")); !errors.Is(err, errSpillFull) {
+		t.Fatalf("push over budget error = %v, want errSpillFull", err)
+	}
+
+	for _, want := range []string{"first", "second"} {
+		got, err := q.pop()
+		if err != nil || string(got) != want {
+			t.Fatalf("pop() = %q, %v, want %q", got, err, want)
+		}
+	}
+	if q.size() != 0 {
+		t.Errorf("size() = %d after drain, want 0", q.size())
+	}
+	if err := q.push([]byte("third")); err != nil {
+		t.Errorf("push after drain failed: %v", err)
+	}
+}
+
+func TestSpill_events(t *testing.T) {
+	block := tmtypes.MakeBlock(10, []tmtypes.Tx{tmtypes.Tx("tx1"), tmtypes.Tx("tx2")}, &tmtypes.Commit{Height: 9}, nil)
+	block.ChainID = "osmosis-1"
+	block.Time = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	txResult := abci.TxResult{
+		Height: 10,
+		Index:  1,
+		Tx:     tmtypes.Tx("tx2"),
+		Result: abci.ResponseDeliverTx{
+			Code:    0,
+			GasUsed: 100,
+			Events: []abci.Event{{
+				Type:       "token_swapped",
+				Attributes: []abci.EventAttribute{{Key: "pool_id", Value: "1", Index: true}, {Key: "tokens_in", Value: "100uosmo"}},
+			}},
+		},
+	}
+	events := []ctypes.ResultEvent{
+		{
+			Query:  "tm.event='NewBlock'",
+			Data:   tmtypes.EventDataNewBlock{Block: block},
+			Events: map[string][]string{"tm.event": {"NewBlock"}},
+		},
+		{
+			Query:  "tm.event='Tx'",
+			Data:   tmtypes.EventDataTx{TxResult: txResult},
+			Events: map[string][]string{"tm.event": {"Tx"}, "tx.height": {"10"}},
+		},
+	}
+
+	c := &rpc{logger: slog.Default()}
+	q, err := newSpillQueue(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("newSpillQueue failed: %v", err)
+	}
+	defer q.close()
+	for _, ev := range events {
+		if err := c.spill(q, ev); err != nil {
+			t.Fatalf("spill(%s) failed: %v", ev.Query, err)
+		}
+	}
+
+	got := c.unspill(q)
+	if got == nil {
+		t.Fatal("unspill() of the block failed")
+	}
+	newBlock, ok := got.Data.(tmtypes.EventDataNewBlock)
+	if !ok {
+		t.Fatalf("unspill() data = %T, want EventDataNewBlock", got.Data)
+	}
+	if newBlock.Block.Hash().String() != block.Hash().String() || !newBlock.Block.Time.Equal(block.Time) || len(newBlock.Block.Txs) != 2 {
+		t.Errorf("unspill() block = %v, want %v", newBlock.Block, block)
+	}
+	if !reflect.DeepEqual(got.Events, events[0].Events) {
+		t.Errorf("unspill() events = %v, want %v", got.Events, events[0].Events)
+	}
+
+	got = c.unspill(q)
+	if got == nil {
+		t.Fatal("unspill() of the tx failed")
+	}
+	tx, ok := got.Data.(tmtypes.EventDataTx)
+	if !ok {
+		t.Fatalf("unspill() data = %T, want EventDataTx", got.Data)
+	}
+	if !reflect.DeepEqual(tx.TxResult, txResult) {
+		t.Errorf("unspill() tx = %+v, want %+v", tx.TxResult, txResult)
+	}
+	if c.evtSkipCounter.Load() != 0 {
+		t.Errorf("skipped = %d, want 0", c.evtSkipCounter.Load())
+	}
+}
+
+func TestBufferChannelSpill(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	group, ctx := errgroup.WithContext(ctx)
+	c := &rpc{
+		ctx:           ctx,
+		group:         group,
+		logger:        slog.Default(),
+		spillDir:      t.TempDir(),
+		spillMaxBytes: 1 << 20,
+	}
+
+	const total = 100
+	events := make(chan ctypes.ResultEvent)
+	out := c.bufferChannel("test", events, 2)
+	for i := 0; i < total; i++ {
+		events <- ctypes.ResultEvent{Query: strconv.Itoa(i)}
+	}
+
+	for i := 0; i < total; i++ {
+		ev := <-out
+		if ev.Query != strconv.Itoa(i) {
+			t.Fatalf("event %d = %s, want in order", i, ev.Query)
+		}
+	}
+	if skipped := c.evtSkipCounter.Load(); skipped != 0 {
+		t.Errorf("skipped = %d, want 0", skipped)
+	}
+	if spilled := c.evtSpillCounter.Load(); spilled == 0 {
+		t.Errorf("spilled = 0, want events to be spilled")
+	}
+
+	cancel()
+	group.Wait()
+}
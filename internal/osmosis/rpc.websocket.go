@@ -0,0 +1,205 @@
+package osmosis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+)
+
+const (
+	// websocketStaleTimeout is the period without any events after which the connection is considered dead.
+	websocketStaleTimeout = time.Minute
+	websocketCheckPeriod  = time.Second * 10
+	reconnectBackoffMin   = time.Second
+	reconnectBackoffMax   = time.Minute
+)
+
+var errWebsocketStale = errors.New("websocket stopped delivering events")
+
+// subscription is a registered websocket query. events is fed by whatever connection is currently active
+// and every event is passed on to all the consumers, so that the query is issued once however many handlers need it.
+type subscription struct {
+	query  string
+	events chan ctypes.ResultEvent
+
+	mu        sync.Mutex
+	consumers []chan ctypes.ResultEvent
+}
+
+func (s *subscription) addConsumer(consumer chan ctypes.ResultEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consumers = append(s.consumers, consumer)
+}
+
+// fanOut passes the events of the subscription on to its consumers until ctx is done.
+func (s *subscription) fanOut(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-s.events:
+			s.mu.Lock()
+			consumers := s.consumers
+			s.mu.Unlock()
+			for _, consumer := range consumers {
+				select {
+				case <-ctx.Done():
+					return nil
+				case consumer <- ev:
+				}
+			}
+		}
+	}
+}
+
+func (c *rpc) dialWebsocket(url string) (*eventsClient, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, websocketHandshakeTimeout)
+	defer cancel()
+	return c.credentials.dialEvents(ctx, url, c.logger)
+}
+
+// subscribe issues the subscription query on the client and forwards its events
+// into the subscription channel until connCtx is cancelled.
+func (c *rpc) subscribe(connCtx context.Context, client *eventsClient, sub *subscription) error {
+	ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
+	events, err := client.Subscribe(ctx, sub.query, 10)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	c.group.Go(func() error {
+		for {
+			select {
+			case <-connCtx.Done():
+				return nil
+			case ev := <-events:
+				c.lastEvent.Store(time.Now().UnixNano())
+				if block, ok := ev.Data.(tmtypes.EventDataNewBlock); ok {
+					setMaxValue(&c.latestHeight, uint64(block.Block.Height))
+				}
+				select {
+				case <-connCtx.Done():
+					return nil
+				case sub.events <- ev:
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// monitorConnection periodically checks whether the websocket is still delivering events
+// and reconnects if it is not.
+func (c *rpc) monitorConnection() error {
+	ticker := time.NewTicker(websocketCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.logger.Info("rpc.monitorConnection: Context Done")
+			return nil
+		case <-ticker.C:
+		}
+
+		if c.isConnectionHealthy() {
+			continue
+		}
+		c.reconnect()
+	}
+}
+
+func (c *rpc) isConnectionHealthy() bool {
+	c.subscriptionsMu.Lock()
+	numSubscriptions := len(c.subscriptions)
+	c.subscriptionsMu.Unlock()
+
+	if !c.websocket.Load().IsRunning() {
+		return false
+	}
+	if numSubscriptions == 0 {
+		return true
+	}
+
+	return time.Since(time.Unix(0, c.lastEvent.Load())) < websocketStaleTimeout
+}
+
+// reconnect will redial Tendermint with exponential backoff until all the subscriptions are re-issued.
+func (c *rpc) reconnect() {
+	lastEvent := time.Unix(0, c.lastEvent.Load())
+	c.logger.Warn("Websocket connection lost, reconnecting", "endpoint", c.websocketURL(), "last_event", time.Since(lastEvent))
+
+	c.subscriptionsMu.Lock()
+	c.wsEndpoint.observe(0, errWebsocketStale)
+	c.subscriptionsMu.Unlock()
+
+	backoff := reconnectBackoffMin
+	for {
+		err := c.resubscribe()
+		if err == nil {
+			break
+		}
+		c.errCounter.Add(1)
+		c.logger.Error("Websocket reconnect failed", "err", err, "retry_in", backoff)
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, reconnectBackoffMax)
+	}
+
+	downtime := time.Since(lastEvent)
+	c.reconnectCounter.Add(1)
+	c.downtime.Add(int64(downtime))
+	c.lastEvent.Store(time.Now().UnixNano())
+	c.logger.Info("Websocket reconnected", "endpoint", c.websocketURL(), "downtime", downtime)
+}
+
+// resubscribe creates a new websocket client on the healthiest Tendermint endpoint,
+// re-issues every registered query on it and replaces the old client.
+func (c *rpc) resubscribe() error {
+	e := c.tendermints.best()
+	client, err := c.dialWebsocket(e.url)
+	if err != nil {
+		e.observe(0, err)
+		return fmt.Errorf("%s: %w", e.url, err)
+	}
+
+	c.subscriptionsMu.Lock()
+	defer c.subscriptionsMu.Unlock()
+
+	connCtx, connCancel := context.WithCancel(c.ctx)
+	for _, sub := range c.subscriptions {
+		if err := c.subscribe(connCtx, client, sub); err != nil {
+			connCancel()
+			client.Stop()
+			e.observe(0, err)
+			return fmt.Errorf("%s: failed subscribing to %q: %w", e.url, sub.query, err)
+		}
+	}
+
+	c.connCancel()
+	c.connCtx, c.connCancel = connCtx, connCancel
+	c.wsEndpoint = e
+
+	old := c.websocket.Swap(client)
+	if err := old.Stop(); err != nil {
+		c.logger.Debug("Stopping old Tendermint client failed", "err", err)
+	}
+
+	return nil
+}
+
+func (c *rpc) websocketURL() string {
+	c.subscriptionsMu.Lock()
+	defer c.subscriptionsMu.Unlock()
+	return c.wsEndpoint.url
+}
@@ -0,0 +1,39 @@
+package osmosis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+)
+
+func TestSubscriptionFanOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub := &subscription{
+		query:  "tm.event='Tx'",
+		events: make(chan ctypes.ResultEvent),
+	}
+	consumers := []chan ctypes.ResultEvent{make(chan ctypes.ResultEvent, 2), make(chan ctypes.ResultEvent, 2)}
+	for _, consumer := range consumers {
+		sub.addConsumer(consumer)
+	}
+	go sub.fanOut(ctx)
+
+	sub.events <- ctypes.ResultEvent{Query: "a"}
+	sub.events <- ctypes.ResultEvent{Query: "b"}
+	for i, consumer := range consumers {
+		for _, want := range []string{"a", "b"} {
+			select {
+			case ev := <-consumer:
+				if ev.Query != want {
+					t.Errorf("consumer %d got %q, want %q", i, ev.Query, want)
+				}
+			case <-ctx.Done():
+				t.Fatalf("consumer %d got no event %q", i, want)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package osmosis
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -8,13 +9,52 @@ import (
 	tmtypes "github.com/cometbft/cometbft/types"
 )
 
+const (
+	// Checkpoint subjects of the last published heights.
+	checkpointBlocks = "block"
+	checkpointTxs    = "tx"
+	// checkpointPeriod is how often checkpoints are saved. Heights published since the last save
+	// are published again after a crash and dropped by JetStream deduplication.
+	checkpointPeriod = 10 * time.Second
+	// publishedTxsDedupSize is the number of recent transaction hashes remembered so that
+	// backfilling does not publish transactions that were delivered by the subscription.
+	publishedTxsDedupSize = 1 << 16
+	// poolJobsQueueSize is the number of blocks waiting for pool-of-interest computation.
+	poolJobsQueueSize = 64
+)
+
+// blockSource fetches historical blocks for backfilling.
+type blockSource interface {
+	BlockAt(height int64) (*tmtypes.Block, error)
+	BlockResultsAt(height int64) (*ctypes.ResultBlockResults, error)
+}
+
+// poolJob is a block waiting for pool-of-interest computation.
+type poolJob struct {
+	height   int64
+	time     time.Time
+	hash     string
+	deadline time.Time
+}
+
+// expired reports whether the result would be too stale to be published.
+func (j poolJob) expired(now time.Time) bool {
+	return !j.deadline.IsZero() && now.After(j.deadline)
+}
+
 func (p *Publisher) subscribeBlocks() error {
+	if p.StreamEnabled(StreamVolumePool) {
+		p.Group.Go(p.handlePoolJobs)
+	}
 	return p.rpc.Subscribe(fmt.Sprintf("tm.event='%s'", tmtypes.EventNewBlock), p.handleBlocks)
 }
 
+// handleBlocks publishes blocks as soon as they arrive and hands pool-of-interest
+// computation over to handlePoolJobs so that it does not delay the block stream.
 func (p *Publisher) handleBlocks(events <-chan ctypes.ResultEvent) error {
-	sentinel := p.MakeSentinel(time.Minute)
-
+	if !p.waitCaughtUp() {
+		return nil
+	}
 	for {
 		select {
 		case <-p.Context.Done():
@@ -26,18 +66,9 @@ func (p *Publisher) handleBlocks(events <-chan ctypes.ResultEvent) error {
 				return nil
 			}
 
-			if err := sentinel(); err != nil {
-				return err
-			}
-
 			switch data := ev.Data.(type) {
 			case tmtypes.EventDataNewBlock:
-				now := time.Now()
-				p.Logger.Info("Block START", "hash", data.Block.Hash().String(), "height", data.Block.Height, "time", data.Block.Time, "len(events)", len(events))
-				p.indexer.SetLatestBlockHeight(uint64(data.Block.Height), data.Block.Time)
-				p.handleBlock(data.Block)
-				p.handleMonitoredPools(data.Block.Height, data.Block.Time, data.Block.Hash().String())
-				p.Logger.Info("Block FINISH", "hash", data.Block.Hash().String(), "height", data.Block.Height, "duration", time.Since(now), "len(events)", len(events))
+				p.handleNewBlock(data.Block, len(events))
 			default:
 				p.evtOtherCounter.Add(1)
 			}
@@ -45,16 +76,225 @@ func (p *Publisher) handleBlocks(events <-chan ctypes.ResultEvent) error {
 	}
 }
 
+// handleNewBlock backfills the blocks missed before the live block and publishes it.
+// The live block is held back until the missed ones are published, so that no gap is left behind it.
+func (p *Publisher) handleNewBlock(block *tmtypes.Block, queueSize int) {
+	if p.StreamEnabled(StreamStatePools) {
+		p.poolBatches.blockSeen(block.Height, block.Hash().String())
+	}
+	if p.txStreamsEnabled() {
+		p.txHeights.setCount(block.Height, len(block.Txs))
+	}
+	if !p.backfillBlocks(block.Height) {
+		return
+	}
+	now := time.Now()
+	p.Logger.Info("Block START", "hash", block.Hash().String(), "height", block.Height, "time", block.Time, "len(events)", queueSize)
+	if p.poolStreamsEnabled() {
+		p.indexer.SetLatestBlockHeight(uint64(block.Height), block.Time)
+	}
+	p.handleBlock(block)
+	if p.txStreamsEnabled() {
+		p.txMu.Lock()
+		err := p.completeTxs(block.Height)
+		p.txMu.Unlock()
+		if err != nil {
+			p.errCounter.Add(1)
+			p.Logger.Error("Backfilling transactions failed, retrying on the next block", "height", block.Height, "err", err)
+		}
+	}
+	if p.StreamEnabled(StreamVolumePool) {
+		p.enqueuePoolJob(block, now)
+	}
+	p.Logger.Info("Block FINISH", "hash", block.Hash().String(), "height", block.Height, "duration", time.Since(now), "len(events)", queueSize)
+}
+
+// enqueuePoolJob queues pool-of-interest computation of the block. If the worker is too far behind,
+// the oldest queued block is skipped to make room.
+func (p *Publisher) enqueuePoolJob(block *tmtypes.Block, received time.Time) {
+	job := poolJob{
+		height: block.Height,
+		time:   block.Time,
+		hash:   block.Hash().String(),
+	}
+	if deadline := p.BlockDeadline(); deadline > 0 {
+		job.deadline = received.Add(deadline)
+	}
+
+	for {
+		select {
+		case p.poolJobs <- job:
+			return
+		default:
+		}
+
+		select {
+		case old := <-p.poolJobs:
+			p.poolJobsSkipped.Add(1)
+			p.Logger.Warn("Pool jobs queue is full, skipping block", "height", old.height)
+		default:
+		}
+	}
+}
+
+// handlePoolJobs computes pools of interest block by block, so that the messages are published in block order.
+// Blocks that are past their deadline are skipped.
+func (p *Publisher) handlePoolJobs() error {
+	for {
+		select {
+		case <-p.Context.Done():
+			p.Logger.Info("handlePoolJobs: c.Context Done")
+			return nil
+		case job := <-p.poolJobs:
+			if job.expired(time.Now()) {
+				p.poolJobsSkipped.Add(1)
+				p.Logger.Warn("Skipping stale pool computation", "height", job.height, "deadline", job.deadline)
+				continue
+			}
+			p.handleMonitoredPools(job)
+		}
+	}
+}
+
+// handleBlock publishes the block. When the block stream is disabled, only the height is tracked.
 func (p *Publisher) handleBlock(block *tmtypes.Block) {
 	p.blockCounter.Add(1)
 	p.blockHeight.Set(float64(block.Height))
+	p.latestBlock.Store(&blockStamp{height: block.Height, time: block.Time})
 	p.blocksCounter.Add(1)
+	if !p.StreamEnabled(StreamBlock) {
+		p.publishedHeight.Store(uint64(block.Height))
+		return
+	}
 
 	outBlock := p.rpc.translateBlock(block)
 	outBlock.Nonce = p.NewNonce()
-	p.Publish(
+	p.PublishWithID(
 		outBlock,
+		blockMsgID(block.ChainID, block.Height),
 		"block",
 	)
+	p.publishedHeight.Store(uint64(block.Height))
 	p.messagesCounter.Add(1)
 }
+
+// restoreCheckpoints loads the last published heights, so that the blocks and transactions
+// produced while the publisher was down are backfilled by catchUp.
+// Checkpoints of disabled streams are neither restored nor saved.
+func (p *Publisher) restoreCheckpoints() {
+	if height, found := p.db.Checkpoint(checkpointBlocks); found && p.StreamEnabled(StreamBlock) {
+		p.publishedHeight.Store(height)
+		p.Logger.Info("Resuming blocks", "checkpoint", height)
+	}
+	if height, found := p.db.Checkpoint(checkpointTxs); found && p.txStreamsEnabled() {
+		p.txDoneHeight.Store(height)
+		p.Logger.Info("Resuming transactions", "checkpoint", height)
+	}
+}
+
+// saveCheckpoints persists the last published block height and the last height whose transactions were all published.
+func (p *Publisher) saveCheckpoints() error {
+	var errArr []error
+	if height := p.publishedHeight.Load(); height > 0 && p.StreamEnabled(StreamBlock) {
+		errArr = append(errArr, p.db.SaveCheckpoint(checkpointBlocks, height))
+	}
+	if height := p.txDoneHeight.Load(); height > 0 && p.txStreamsEnabled() {
+		errArr = append(errArr, p.db.SaveCheckpoint(checkpointTxs, height))
+	}
+	return errors.Join(errArr...)
+}
+
+// handleCheckpoints periodically saves checkpoints, so that a slow database does not delay publishing.
+// Close saves them one last time.
+func (p *Publisher) handleCheckpoints() error {
+	ticker := time.NewTicker(checkpointPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.Context.Done():
+			p.Logger.Info("handleCheckpoints: c.Context Done")
+			return nil
+		case <-ticker.C:
+			if err := p.saveCheckpoints(); err != nil {
+				p.Logger.Warn("Saving checkpoints failed", "err", err)
+			}
+		}
+	}
+}
+
+// catchUp backfills the blocks and transactions produced while the publisher was down.
+// Live events are buffered by the subscriptions meanwhile and handled only once it finishes,
+// so that the subjects are published in height order. If backfilling fails, the first live
+// block is held back and the same range is retried on the next one.
+func (p *Publisher) catchUp() error {
+	defer close(p.caughtUp)
+	height, err := p.rpc.LatestBlockHeight()
+	if err != nil {
+		p.Logger.Warn("Fetching latest block height failed, catching up on the first live block", "err", err)
+		return nil
+	}
+	p.backfillBlocks(height + 1)
+	if p.txStreamsEnabled() {
+		p.txMu.Lock()
+		err := p.completeTxs(height + 1)
+		p.txMu.Unlock()
+		if err != nil {
+			p.errCounter.Add(1)
+			p.Logger.Error("Backfilling transactions failed, retrying on the first live block", "err", err)
+		}
+	}
+	return nil
+}
+
+// waitCaughtUp blocks until catchUp has finished. Returns false if the publisher is stopping.
+func (p *Publisher) waitCaughtUp() bool {
+	select {
+	case <-p.caughtUp:
+		return true
+	case <-p.Context.Done():
+		return false
+	}
+}
+
+// backfillBlocks publishes blocks that were missed before height.
+// Returns false if the block at height was already published or the missed blocks could not be
+// published, so that the block at height must be skipped. The missed blocks are then retried
+// on the next block, until they fall behind MaxCatchupBlocks.
+func (p *Publisher) backfillBlocks(height int64) bool {
+	last := int64(p.publishedHeight.Load())
+	if last != 0 && height <= last {
+		p.Logger.Warn("Skipping already published block", "height", height, "last_published", last)
+		return false
+	}
+	if last == 0 || !p.StreamEnabled(StreamBlock) {
+		return true
+	}
+
+	from := last + 1
+	if maxLag := int64(p.MaxCatchupBlocks()); height-from > maxLag {
+		p.Logger.Warn("Too many missed blocks, backfilling only the latest", "from", from, "to", height-1, "max", maxLag)
+		from = height - maxLag
+	}
+
+	for h := from; h < height; h++ {
+		if err := p.backfillBlock(h); err != nil {
+			p.errCounter.Add(1)
+			p.Logger.Error("Backfilling block failed, retrying on the next block", "height", h, "err", err)
+			return false
+		}
+	}
+	return true
+}
+
+// backfillBlock fetches a historical block and publishes it.
+func (p *Publisher) backfillBlock(height int64) error {
+	block, err := p.blocks.BlockAt(height)
+	if err != nil {
+		return fmt.Errorf("failed fetching block: %w", err)
+	}
+
+	p.Logger.Info("Block BACKFILL", "hash", block.Hash().String(), "height", height, "txs", len(block.Txs))
+	p.handleBlock(block)
+	p.backfillCounter.Add(1)
+	return nil
+}
@@ -0,0 +1,155 @@
+package osmosis
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/dtlWithSocket"
+)
+
+// newTestPublisher creates a publisher without connections. Published messages are dropped.
+func newTestPublisher(t *testing.T, opts ...options.Option) *Publisher {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Publisher{
+		Service:             dtlWithSocket.NewNatsAndSocketConn(),
+		db:                  checkpointRepository{checkpoints: make(map[string]uint64)},
+		publishedTxs:        newRecentSet(publishedTxsDedupSize),
+		txHeights:           newTxHeights(),
+		caughtUp:            make(chan struct{}),
+		blocksCounter:       prometheus.NewCounter(prometheus.CounterOpts{Name: "blocks"}),
+		transactionsCounter: prometheus.NewCounter(prometheus.CounterOpts{Name: "transactions"}),
+		messagesCounter:     prometheus.NewCounter(prometheus.CounterOpts{Name: "messages"}),
+		blockHeight:         prometheus.NewGauge(prometheus.GaugeOpts{Name: "height"}),
+	}
+	opts = append([]options.Option{
+		func(o *options.Options) { o.PrivateKey = key },
+		WithStream(StreamStatePools, false),
+		WithStream(StreamVolumePool, false),
+	}, opts...)
+	if err := p.Configure(opts...); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	return p
+}
+
+// liveTx returns the transaction event of the only transaction of the block.
+func (c *testChain) liveTx(height int64) tmtypes.EventDataTx {
+	block := c.block(height)
+	return tmtypes.EventDataTx{TxResult: abci.TxResult{Height: height, Tx: block.Txs[0]}}
+}
+
+// testChain serves blocks of one transaction each. BlockAt fails for the heights in failures once.
+type testChain struct {
+	failures map[int64]bool
+	fetched  []int64
+}
+
+func (c *testChain) block(height int64) *tmtypes.Block {
+	block := tmtypes.MakeBlock(height, []tmtypes.Tx{tmtypes.Tx(fmt.Sprintf("tx-%d", height))}, &tmtypes.Commit{Height: height - 1}, nil)
+	block.ChainID = "osmosis-1"
+	block.Time = time.Unix(height, 0)
+	return block
+}
+
+func (c *testChain) BlockAt(height int64) (*tmtypes.Block, error) {
+	c.fetched = append(c.fetched, height)
+	if c.failures[height] {
+		delete(c.failures, height)
+		return nil, errors.New("connection refused")
+	}
+	return c.block(height), nil
+}
+
+func (c *testChain) BlockResultsAt(height int64) (*ctypes.ResultBlockResults, error) {
+	return &ctypes.ResultBlockResults{Height: height, TxsResults: []*abci.ResponseDeliverTx{{}}}, nil
+}
+
+func TestPublisher_backfillFailure(t *testing.T) {
+	p := newTestPublisher(t, WithStream(StreamTx, false), WithStream(StreamSwaps, false), WithMaxCatchupBlocks(10))
+	chain := &testChain{failures: map[int64]bool{11: true}}
+	p.blocks = chain
+	p.publishedHeight.Store(10)
+
+	p.handleNewBlock(chain.block(13), 0)
+	if got := p.publishedHeight.Load(); got != 10 {
+		t.Fatalf("published height = %d, want 10 while block 11 is missing", got)
+	}
+
+	p.handleNewBlock(chain.block(14), 0)
+	if got := p.publishedHeight.Load(); got != 14 {
+		t.Errorf("published height = %d, want 14", got)
+	}
+	if got := p.backfillCounter.Load(); got != 3 {
+		t.Errorf("backfilled blocks = %d, want 11, 12 and 13", got)
+	}
+	if want := []int64{11, 11, 12, 13}; !reflect.DeepEqual(chain.fetched, want) {
+		t.Errorf("fetched heights = %v, want %v", chain.fetched, want)
+	}
+}
+
+func TestPublisher_txHeightsDone(t *testing.T) {
+	p := newTestPublisher(t, WithStream(StreamBlock, false), WithStream(StreamTx, false), WithStream(StreamSwaps, true))
+	chain := &testChain{}
+	p.blocks = chain
+	p.txDoneHeight.Store(9)
+
+	p.handleNewBlock(chain.block(10), 0)
+	p.handleTransaction(chain.liveTx(10), 0, time.Time{})
+	p.handleNewBlock(chain.block(11), 0)
+	if got := p.txDoneHeight.Load(); got != 10 {
+		t.Errorf("tx done height = %d, want 10 once block 11 arrived", got)
+	}
+	if len(chain.fetched) != 0 {
+		t.Errorf("fetched heights = %v, want none for complete heights", chain.fetched)
+	}
+
+	// The transaction of 11 was not delivered and block 12 was missed.
+	p.handleNewBlock(chain.block(13), 0)
+	if got := p.txDoneHeight.Load(); got != 12 {
+		t.Errorf("tx done height = %d, want 12", got)
+	}
+	if want := []int64{11, 12}; !reflect.DeepEqual(chain.fetched, want) {
+		t.Errorf("fetched heights = %v, want %v", chain.fetched, want)
+	}
+	if got := p.txCounter.Load(); got != 3 {
+		t.Errorf("published txs = %d, want 3", got)
+	}
+}
+
+func TestPublisher_liveTxOrder(t *testing.T) {
+	p := newTestPublisher(t, WithStream(StreamBlock, false), WithStream(StreamTx, false), WithStream(StreamSwaps, true))
+	chain := &testChain{failures: map[int64]bool{11: true}}
+	p.blocks = chain
+	p.txDoneHeight.Store(10)
+	p.txHeights.setCount(11, 1)
+
+	// The transaction of 11 was lost, so 12 waits for its backfill, which fails once.
+	p.handleLiveTransaction(chain.liveTx(12), 0)
+	if got := p.txCounter.Load(); got != 0 {
+		t.Fatalf("published txs = %d, want none before height 11 is backfilled", got)
+	}
+
+	p.handleLiveTransaction(chain.liveTx(13), 0)
+	if got := p.txDoneHeight.Load(); got != 12 {
+		t.Errorf("tx done height = %d, want 12", got)
+	}
+	if want := []int64{11, 11, 12}; !reflect.DeepEqual(chain.fetched, want) {
+		t.Errorf("fetched heights = %v, want %v", chain.fetched, want)
+	}
+	if got := p.txCounter.Load(); got != 3 {
+		t.Errorf("published txs = %d, want 11, 12 and 13", got)
+	}
+}
@@ -5,31 +5,226 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/synternet/osmosis-publisher/pkg/types"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	cltypes "github.com/osmosis-labs/osmosis/v24/x/concentrated-liquidity/types"
 	wasmtypes "github.com/osmosis-labs/osmosis/v24/x/cosmwasmpool/types"
 	gammtypes "github.com/osmosis-labs/osmosis/v24/x/gamm/types"
 	pmtypes "github.com/osmosis-labs/osmosis/v24/x/poolmanager/types"
 
+	"github.com/cometbft/cometbft/libs/pubsub/query"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
 )
 
+// DefaultEventQueries are the Tendermint queries of Osmosis modules that touch liquidity pools.
+var DefaultEventQueries = []string{
+	fmt.Sprintf("message.module='%s'", pmtypes.AttributeValueCategory),
+	fmt.Sprintf("message.module='%s'", gammtypes.AttributeValueCategory),
+	fmt.Sprintf("message.module='%s'", wasmtypes.AttributeValueCategory),
+	fmt.Sprintf("message.module='%s'", cltypes.AttributeValueCategory),
+}
+
+// subscribeOsmosisEvents matches the events of every transaction against the event queries. Queries are not subscribed
+// on their own, since nodes limit subscriptions per client (max_subscriptions_per_client, 5 by default) and
+// the transaction subscription is shared with the tx streams.
 func (p *Publisher) subscribeOsmosisEvents() error {
-	queries := []string{
-		fmt.Sprintf("message.module='%s'", pmtypes.AttributeValueCategory),
-		fmt.Sprintf("message.module='%s'", gammtypes.AttributeValueCategory),
-		fmt.Sprintf("message.module='%s'", wasmtypes.AttributeValueCategory),
-		fmt.Sprintf("message.module='%s'", cltypes.AttributeValueCategory),
+	queries, err := parseEventQueries(p.EventQueries())
+	if err != nil {
+		return err
+	}
+	if err := p.rpc.Subscribe(fmt.Sprintf("tm.event='%s'", tmtypes.EventTx), func(events <-chan ctypes.ResultEvent) error {
+		return p.handlePoolSubscriptions(queries, events)
+	}); err != nil {
+		return fmt.Errorf("failed subscribing to txs: %w", err)
+	}
+	p.Group.Go(p.handlePoolBatches)
+
+	return nil
+}
+
+func parseEventQueries(queries []string) ([]*query.Query, error) {
+	ret := make([]*query.Query, len(queries))
+	for i, q := range queries {
+		parsed, err := query.New(q)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event query %q: %w", q, err)
+		}
+		ret[i] = parsed
 	}
+	return ret, nil
+}
 
+// matchEventQueries reports whether the events match any of the queries.
+func matchEventQueries(queries []*query.Query, events map[string][]string) bool {
 	for _, q := range queries {
-		return p.rpc.Subscribe(q, p.handlePoolSubscriptions)
+		if ok, err := q.Matches(events); err == nil && ok {
+			return true
+		}
 	}
+	return false
+}
 
-	return nil
+const (
+	poolBatchCheckPeriod = time.Millisecond * 100
+	// poolBatchKeep is the number of heights a published batch is kept for, so that late events are merged into it.
+	poolBatchKeep = 10
+)
+
+// poolBatch is the set of pools touched by the events of a single block.
+// Batches with late events are published again as revisions that include the earlier events.
+type poolBatch struct {
+	height   int64
+	revision int
+	poolIds  map[uint64]struct{}
+	events   map[string][]string
+	// hash is the hash of the block, empty if the block was not seen.
+	hash string
+}
+
+func newPoolBatch(height int64) *poolBatch {
+	return &poolBatch{
+		height:  height,
+		poolIds: make(map[uint64]struct{}),
+		events:  make(map[string][]string),
+	}
+}
+
+// ids returns sorted pool ids of the batch.
+func (b *poolBatch) ids() []uint64 {
+	ids := maps.Keys(b.poolIds)
+	slices.Sort(ids)
+	return ids
+}
+
+func (b *poolBatch) merge(poolIds []uint64, events map[string][]string) {
+	for _, id := range poolIds {
+		b.poolIds[id] = struct{}{}
+	}
+	for k, v := range events {
+		b.events[k] = append(b.events[k], v...)
+	}
+}
+
+// next returns the next revision of the batch with the same pools and events.
+func (b *poolBatch) next() *poolBatch {
+	batch := newPoolBatch(b.height)
+	batch.revision = b.revision + 1
+	batch.hash = b.hash
+	batch.merge(maps.Keys(b.poolIds), b.events)
+	return batch
+}
+
+// poolBatcher collects pool events per block height. The batch of a height is complete
+// once the next block is seen, since the events of a block are emitted before the next one is committed.
+type poolBatcher struct {
+	mu        sync.Mutex
+	batches   map[int64]*poolBatch
+	published map[int64]*poolBatch
+	// hashes are the hashes of the seen blocks that may still have batches.
+	hashes map[int64]string
+	// closedHeight is the height below the last seen block.
+	closedHeight int64
+}
+
+func newPoolBatcher() *poolBatcher {
+	return &poolBatcher{
+		batches:   make(map[int64]*poolBatch),
+		published: make(map[int64]*poolBatch),
+		hashes:    make(map[int64]string),
+	}
+}
+
+// add merges pool ids and events into the batch of the height.
+func (b *poolBatcher) add(height int64, poolIds []uint64, events map[string][]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch, found := b.batches[height]
+	if !found {
+		batch = newPoolBatch(height)
+		if prev, found := b.published[height]; found {
+			batch = prev.next()
+		}
+		b.batches[height] = batch
+	}
+	batch.merge(poolIds, events)
+}
+
+// blockSeen records the hash of the block and completes the batches below its height.
+func (b *poolBatcher) blockSeen(height int64, hash string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hashes[height] = hash
+	b.closedHeight = max(b.closedHeight, height-1)
+}
+
+// ready removes and returns the complete batches ordered by height.
+func (b *poolBatcher) ready() []*poolBatch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var ret []*poolBatch
+	for height, batch := range b.batches {
+		if height <= b.closedHeight {
+			if batch.hash == "" {
+				batch.hash = b.hashes[height]
+			}
+			ret = append(ret, batch)
+			delete(b.batches, height)
+			b.published[height] = batch
+		}
+	}
+	for height := range b.published {
+		if height <= b.closedHeight-poolBatchKeep {
+			delete(b.published, height)
+		}
+	}
+	for height := range b.hashes {
+		if height <= b.closedHeight-poolBatchKeep {
+			delete(b.hashes, height)
+		}
+	}
+	slices.SortFunc(ret, func(a, b *poolBatch) bool {
+		return a.height < b.height
+	})
+	return ret
+}
+
+// recentSet remembers a bounded number of the most recently added keys.
+type recentSet struct {
+	mu    sync.Mutex
+	keys  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newRecentSet(size int) *recentSet {
+	return &recentSet{
+		keys:  make(map[string]struct{}, size),
+		order: make([]string, size),
+	}
+}
+
+// add reports whether key was not seen before. The oldest key is forgotten when the set is full.
+func (s *recentSet) add(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.keys[key]; found {
+		return false
+	}
+	delete(s.keys, s.order[s.next])
+	s.order[s.next] = key
+	s.next = (s.next + 1) % len(s.order)
+	s.keys[key] = struct{}{}
+	return true
 }
 
 // combinePoolStatusesAt Fetch pool volume&liquidity at certain height and append to appropriate pool Volumes.
@@ -89,13 +284,13 @@ func (p *Publisher) getPoolsOfInterestStatuses(height int64, ids ...uint64) ([]t
 }
 
 // handleMonitoredPools will retrieve pools for different heights configured to be monitored,
-// calculate volume prices and send as a message.
-func (p *Publisher) handleMonitoredPools(height int64, blockTime time.Time, hash string) {
+// calculate volume prices and send as a message. The message is dropped if it is ready after the job deadline.
+func (p *Publisher) handleMonitoredPools(job poolJob) {
+	height := job.height
 	poolStatus := types.PoolOfInterest{
-		Nonce:        p.NewNonce(),
 		BlockHeight:  height,
 		AvgBlockTime: p.indexer.AverageBlockTime().Seconds(),
-		BlockHash:    hash,
+		BlockHash:    job.hash,
 	}
 
 	now := time.Now()
@@ -122,18 +317,30 @@ func (p *Publisher) handleMonitoredPools(height int64, blockTime time.Time, hash
 	}
 	poolStatus.Metadata = ibcMap
 
-	p.Logger.Info("Pool Volumes", "num_pools", len(poolStatus.Pools), "height", height, "blockTime", blockTime, "duration", time.Since(now))
-	p.Publish(
+	if job.expired(time.Now()) {
+		p.poolJobsSkipped.Add(1)
+		p.Logger.Warn("Pool computation exceeded deadline, skipping", "height", height, "duration", time.Since(now))
+		return
+	}
+
+	p.Logger.Info("Pool Volumes", "num_pools", len(poolStatus.Pools), "height", height, "blockTime", job.time, "duration", time.Since(now))
+	poolStatus.Nonce = p.NewNonce()
+	p.PublishWithID(
 		&poolStatus,
+		poolsMsgID("volume.pool", height, p.PoolIds()),
 		"volume",
 		"pool",
 	)
 	p.messagesCounter.Add(1)
+
+	if p.PoolSubjects() {
+		p.publishPoolVolumes(poolStatus)
+	}
 }
 
-// handlePoolSubscriptions will parse events, determine what pools were involved,
-// retrieve states/volumes/liquidity for such pools, calculate volume prices, and send the message.
-func (p *Publisher) handlePoolSubscriptions(events <-chan ctypes.ResultEvent) error {
+// handlePoolSubscriptions will parse events of the transactions that match the queries, determine what pools were involved and
+// collect them into per block batches that are published by handlePoolBatches.
+func (p *Publisher) handlePoolSubscriptions(queries []*query.Query, events <-chan ctypes.ResultEvent) error {
 	for {
 		select {
 		case <-p.Context.Done():
@@ -145,7 +352,10 @@ func (p *Publisher) handlePoolSubscriptions(events <-chan ctypes.ResultEvent) er
 				return nil
 			}
 
-			ibcMap := make(IBCDenomTrace)
+			if !matchEventQueries(queries, ev.Events) {
+				continue
+			}
+
 			poolIds := ExtractUniquePoolIds(ev)
 
 			p.Logger.Debug("Pool", "query", ev.Query, "poolIds", poolIds)
@@ -154,75 +364,197 @@ func (p *Publisher) handlePoolSubscriptions(events <-chan ctypes.ResultEvent) er
 				continue
 			}
 
-			// TODO: Fetch in parallel
-			poolResults, err := p.rpc.PoolsAt(0, poolIds...)
-			if err != nil {
-				p.Logger.Warn("Failed to fetch pools", "poolIds", poolIds, "err", err)
-			}
-			poolStatuses, height, err := p.getPoolsOfInterestStatuses(0, poolIds...)
-			if err != nil {
-				p.Logger.Warn("Failed getting pools of interest", "err", err)
+			height, ok := eventHeight(ev)
+			if !ok {
+				p.Logger.Warn("Pool event without height", "query", ev.Query, "poolIds", poolIds)
+				continue
 			}
 
-			pools := make([]any, 0, len(poolResults))
-			for idx, pool := range poolResults {
-				if pool == nil {
-					p.Logger.Debug("Pool is nil", "idx", idx)
-					continue
-				}
-				pools = append(pools, (*pool).AsSerializablePool())
-			}
+			p.poolBatches.add(height, poolIds, ev.Events)
+		}
+	}
+}
 
-			if len(pools) == 0 {
-				continue
+// handlePoolBatches periodically publishes pool batches of blocks that are followed by a newer block.
+func (p *Publisher) handlePoolBatches() error {
+	ticker := time.NewTicker(poolBatchCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.Context.Done():
+			p.Logger.Info("handlePoolBatches: c.Context Done")
+			return nil
+		case <-ticker.C:
+			for _, batch := range p.poolBatches.ready() {
+				p.publishPoolBatch(batch)
 			}
+		}
+	}
+}
 
-			for _, ps := range poolStatuses {
-				for _, d := range ps.TotalLiquidity {
-					ibcMap.Add(d.Denom)
-				}
-				for _, v := range ps.Volumes {
-					for _, d := range v.Volume {
-						ibcMap.Add(d.Denom)
-					}
-				}
-			}
+// publishPoolBatch retrieves states/volumes/liquidity of the pools in the batch at the batch height,
+// calculates volume prices, and sends the message.
+func (p *Publisher) publishPoolBatch(batch *poolBatch) {
+	ibcMap := make(IBCDenomTrace)
+	poolIds := batch.ids()
 
-			p.poolCounter.Add(uint64(len(pools)))
+	poolResults, err := p.rpc.PoolsAt(batch.height, poolIds...)
+	if err != nil {
+		p.Logger.Warn("Failed to fetch pools", "height", batch.height, "poolIds", poolIds, "err", err)
+	}
+	poolStatuses, _, err := p.getPoolsOfInterestStatuses(batch.height, poolIds...)
+	if err != nil {
+		p.Logger.Warn("Failed getting pools of interest", "height", batch.height, "err", err)
+	}
 
-			hash := ""
-			block, err := p.rpc.BlockAt(int64(height))
-			if err != nil {
-				p.Logger.Warn("Failed getting block", "err", err)
-			} else {
-				height = uint64(block.Height)
-				hash = block.Hash().String()
-			}
+	pools := make([]any, 0, len(poolResults))
+	poolsById := make(map[uint64]any, len(poolResults))
+	for idx, pool := range poolResults {
+		if pool == nil {
+			p.Logger.Debug("Pool is nil", "idx", idx)
+			continue
+		}
+		serializable := (*pool).AsSerializablePool()
+		pools = append(pools, serializable)
+		poolsById[(*pool).GetId()] = serializable
+	}
 
-			err = p.rpc.getDenoms(ibcMap)
-			if err != nil {
-				p.Logger.Warn("Extracting denoms failed", "err", err)
-			}
+	if len(pools) == 0 {
+		return
+	}
 
-			msg := types.Pools{
-				Nonce:        p.NewNonce(),
-				BlockHeight:  int64(height),
-				BlockHash:    hash,
-				AvgBlockTime: p.indexer.AverageBlockTime().Seconds(),
-				Events:       ev.Events,
-				Pools:        pools,
-				PoolStatus:   poolStatuses,
-				Metadata:     ibcMap,
+	for _, ps := range poolStatuses {
+		for _, d := range ps.TotalLiquidity {
+			ibcMap.Add(d.Denom)
+		}
+		for _, v := range ps.Volumes {
+			for _, d := range v.Volume {
+				ibcMap.Add(d.Denom)
 			}
+		}
+	}
 
-			p.Publish(
-				msg,
-				"state",
-				"pools",
-			)
-			p.messagesCounter.Add(1)
+	p.poolCounter.Add(uint64(len(pools)))
+
+	err = p.rpc.getDenoms(ibcMap)
+	if err != nil {
+		p.Logger.Warn("Extracting denoms failed", "err", err)
+	}
+
+	msg := types.Pools{
+		Nonce:        p.NewNonce(),
+		BlockHeight:  batch.height,
+		BlockHash:    batch.hash,
+		AvgBlockTime: p.indexer.AverageBlockTime().Seconds(),
+		Events:       batch.events,
+		Pools:        pools,
+		PoolStatus:   poolStatuses,
+		Metadata:     ibcMap,
+	}
+
+	p.PublishWithID(
+		msg,
+		revisedMsgID(poolsMsgID("state.pools", batch.height, poolIds), batch.revision),
+		"state",
+		"pools",
+	)
+	p.messagesCounter.Add(1)
+
+	if p.PoolSubjects() {
+		p.publishPoolStates(msg, poolsById, batch.revision)
+	}
+}
+
+// publishPoolStates publishes every pool of the message to its own state.pool.{id} subject.
+// Events of the whole block are kept, since they cannot be attributed to a single pool.
+// Revisions of the batch are published with revised ids, the same as the aggregated message.
+func (p *Publisher) publishPoolStates(msg types.Pools, poolsById map[uint64]any, revision int) {
+	resolved, _ := msg.Metadata.(IBCDenomTrace)
+	ids := maps.Keys(poolsById)
+	slices.Sort(ids)
+	for _, id := range ids {
+		statuses := filterPoolStatuses(msg.PoolStatus, id)
+		poolMsg := msg
+		poolMsg.Nonce = p.NewNonce()
+		poolMsg.Pools = []any{poolsById[id]}
+		poolMsg.PoolStatus = statuses
+		poolMsg.Metadata = poolStatusDenoms(statuses, resolved)
+
+		p.PublishWithID(
+			poolMsg,
+			revisedMsgID(poolMsgID("state.pool", id, msg.BlockHeight), revision),
+			"state",
+			"pool",
+			strconv.FormatUint(id, 10),
+		)
+		p.messagesCounter.Add(1)
+	}
+}
+
+// publishPoolVolumes publishes every pool of the message to its own volume.pool.{id} subject.
+func (p *Publisher) publishPoolVolumes(msg types.PoolOfInterest) {
+	resolved, _ := msg.Metadata.(IBCDenomTrace)
+	for _, status := range msg.Pools {
+		statuses := []types.PoolStatus{status}
+		poolMsg := msg
+		poolMsg.Nonce = p.NewNonce()
+		poolMsg.Pools = statuses
+		poolMsg.Metadata = poolStatusDenoms(statuses, resolved)
+
+		p.PublishWithID(
+			&poolMsg,
+			poolMsgID("volume.pool", status.PoolId, msg.BlockHeight),
+			"volume",
+			"pool",
+			strconv.FormatUint(status.PoolId, 10),
+		)
+		p.messagesCounter.Add(1)
+	}
+}
+
+func filterPoolStatuses(statuses []types.PoolStatus, poolId uint64) []types.PoolStatus {
+	var res []types.PoolStatus
+	for _, s := range statuses {
+		if s.PoolId == poolId {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// poolStatusDenoms returns the resolved IBC denoms that are used by the pool statuses.
+func poolStatusDenoms(statuses []types.PoolStatus, resolved IBCDenomTrace) IBCDenomTrace {
+	res := make(IBCDenomTrace)
+	add := func(coins cosmotypes.Coins) {
+		for _, c := range coins {
+			if trace, ok := resolved[c.Denom]; ok {
+				res[c.Denom] = trace
+			}
 		}
 	}
+	for _, s := range statuses {
+		add(s.TotalLiquidity)
+		for _, v := range s.Volumes {
+			add(v.Volume)
+		}
+	}
+	return res
+}
+
+// eventHeight returns the height of the block the event was emitted in.
+func eventHeight(ev ctypes.ResultEvent) (int64, bool) {
+	if data, ok := ev.Data.(tmtypes.EventDataTx); ok {
+		return data.Height, true
+	}
+	heights := ev.Events["tx.height"]
+	if len(heights) == 0 {
+		return 0, false
+	}
+	height, err := strconv.ParseInt(heights[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return height, true
 }
 
 func ExtractUniquePoolIds(ev ctypes.ResultEvent) []uint64 {
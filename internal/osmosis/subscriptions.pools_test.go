@@ -0,0 +1,133 @@
+package osmosis
+
+import (
+	"reflect"
+	"testing"
+
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/osmosis-publisher/pkg/types"
+)
+
+func TestRecentSet(t *testing.T) {
+	s := newRecentSet(2)
+
+	if !s.add("a") {
+		t.Errorf("add(a) = false, want true")
+	}
+	if s.add("a") {
+		t.Errorf("add(a) twice = true, want false")
+	}
+	s.add("b")
+	s.add("c")
+	if !s.add("a") {
+		t.Errorf("add(a) after eviction = false, want true")
+	}
+	if s.add("c") {
+		t.Errorf("add(c) = true, want false")
+	}
+}
+
+func TestMatchEventQueries(t *testing.T) {
+	queries, err := parseEventQueries(DefaultEventQueries)
+	if err != nil {
+		t.Fatalf("parseEventQueries failed: %v", err)
+	}
+	tests := []struct {
+		name   string
+		events map[string][]string
+		want   bool
+	}{
+		{"gamm swap", map[string][]string{"tm.event": {"Tx"}, "message.module": {"bank", "gamm"}}, true},
+		{"concentrated liquidity", map[string][]string{"tm.event": {"Tx"}, "message.module": {"concentratedliquidity"}}, true},
+		{"bank send", map[string][]string{"tm.event": {"Tx"}, "message.module": {"bank"}}, false},
+		{"no modules", map[string][]string{"tm.event": {"Tx"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchEventQueries(queries, tt.events); got != tt.want {
+				t.Errorf("matchEventQueries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseEventQueries([]string{"message.module="}); err == nil {
+		t.Errorf("parseEventQueries() must fail on an invalid query")
+	}
+}
+
+func TestPoolBatcher(t *testing.T) {
+	b := newPoolBatcher()
+
+	b.add(10, []uint64{3, 1}, map[string][]string{"tx.hash": {"A"}})
+	b.add(10, []uint64{1, 2}, map[string][]string{"tx.hash": {"B"}})
+	b.blockSeen(10, "H10")
+	if got := b.ready(); len(got) != 0 {
+		t.Fatalf("ready() = %d batches before a newer block, want 0", len(got))
+	}
+
+	b.add(11, []uint64{5}, nil)
+	b.blockSeen(11, "H11")
+	got := b.ready()
+	if len(got) != 1 || got[0].height != 10 || got[0].revision != 0 {
+		t.Fatalf("ready() = %v, want batch 10", got)
+	}
+	if got[0].hash != "H10" {
+		t.Errorf("hash = %q, want the hash of block 10", got[0].hash)
+	}
+	if ids := got[0].ids(); !reflect.DeepEqual(ids, []uint64{1, 2, 3}) {
+		t.Errorf("ids() = %v, want [1 2 3]", ids)
+	}
+	if events := got[0].events["tx.hash"]; !reflect.DeepEqual(events, []string{"A", "B"}) {
+		t.Errorf("events = %v, want [A B]", events)
+	}
+
+	// A late event of 10 is merged into a revision of the published batch.
+	b.add(10, []uint64{4}, map[string][]string{"tx.hash": {"C"}})
+	b.blockSeen(12, "H12")
+	got = b.ready()
+	if len(got) != 2 || got[0].height != 10 || got[1].height != 11 {
+		t.Fatalf("ready() = %v, want batches 10 and 11", got)
+	}
+	if got[0].revision != 1 || got[0].hash != "H10" || got[1].hash != "H11" {
+		t.Errorf("revision = %d, hashes = %q, %q, want 1, H10 and H11", got[0].revision, got[0].hash, got[1].hash)
+	}
+	if ids := got[0].ids(); !reflect.DeepEqual(ids, []uint64{1, 2, 3, 4}) {
+		t.Errorf("ids() = %v, want [1 2 3 4]", ids)
+	}
+	if events := got[0].events["tx.hash"]; !reflect.DeepEqual(events, []string{"A", "B", "C"}) {
+		t.Errorf("events = %v, want [A B C]", events)
+	}
+
+	b.blockSeen(12+poolBatchKeep, "")
+	b.ready()
+	if len(b.published) != 0 {
+		t.Errorf("published batches = %d, want none after %d blocks", len(b.published), poolBatchKeep)
+	}
+	if _, found := b.hashes[11]; found {
+		t.Errorf("hash of block 11 is kept after %d blocks", poolBatchKeep)
+	}
+}
+
+func Test_poolStatusDenoms(t *testing.T) {
+	resolved := IBCDenomTrace{
+		"ibc/A": ibctypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"},
+		"ibc/B": ibctypes.DenomTrace{Path: "transfer/channel-1", BaseDenom: "uusdc"},
+	}
+	statuses := []types.PoolStatus{
+		{PoolId: 1, TotalLiquidity: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("ibc/A", 1), cosmotypes.NewInt64Coin("uosmo", 1))},
+		{PoolId: 2, Volumes: []types.PoolStatusVolumeAt{{Volume: cosmotypes.NewCoins(cosmotypes.NewInt64Coin("ibc/B", 1))}}},
+	}
+
+	got := poolStatusDenoms(filterPoolStatuses(statuses, 1), resolved)
+	if !reflect.DeepEqual(got, IBCDenomTrace{"ibc/A": resolved["ibc/A"]}) {
+		t.Errorf("pool 1 denoms = %v", got)
+	}
+	got = poolStatusDenoms(filterPoolStatuses(statuses, 2), resolved)
+	if !reflect.DeepEqual(got, IBCDenomTrace{"ibc/B": resolved["ibc/B"]}) {
+		t.Errorf("pool 2 denoms = %v", got)
+	}
+	if got := filterPoolStatuses(statuses, 3); len(got) != 0 {
+		t.Errorf("unknown pool statuses = %v", got)
+	}
+}
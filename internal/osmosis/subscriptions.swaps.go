@@ -0,0 +1,142 @@
+package osmosis
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/synternet/osmosis-publisher/pkg/types/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// swapEvent is emitted by Osmosis for every swap, including every hop of a route.
+	swapEvent = "token_swapped"
+	// swapMaxPriceAge is the largest distance to a price that a swap is valued with.
+	swapMaxPriceAge = 24 * time.Hour
+)
+
+// blockStamp is the height and the time of a block.
+type blockStamp struct {
+	height int64
+	time   time.Time
+}
+
+// tokenSwap is a parsed token_swapped event.
+type tokenSwap struct {
+	// Index is the position of the swap in the transaction, e.g. of a hop in a multi-hop route.
+	Index    uint32
+	Sender   string
+	PoolId   uint64
+	TokenIn  cosmotypes.Coin
+	TokenOut cosmotypes.Coin
+}
+
+// parseSwaps returns swaps of token_swapped events. Events that cannot be parsed are skipped and reported in errs.
+func parseSwaps(events []abci.Event) (swaps []tokenSwap, errs []error) {
+	for _, event := range events {
+		if event.Type != swapEvent {
+			continue
+		}
+		swap, err := parseSwap(event)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		swap.Index = uint32(len(swaps))
+		swaps = append(swaps, swap)
+	}
+	return swaps, errs
+}
+
+func parseSwap(event abci.Event) (tokenSwap, error) {
+	var swap tokenSwap
+	for _, attr := range event.Attributes {
+		var err error
+		switch attr.Key {
+		case "sender":
+			swap.Sender = attr.Value
+		case "pool_id":
+			swap.PoolId, err = strconv.ParseUint(attr.Value, 10, 64)
+		case "tokens_in":
+			swap.TokenIn, err = cosmotypes.ParseCoinNormalized(attr.Value)
+		case "tokens_out":
+			swap.TokenOut, err = cosmotypes.ParseCoinNormalized(attr.Value)
+		}
+		if err != nil {
+			return tokenSwap{}, fmt.Errorf("bad %s %s %q: %w", swapEvent, attr.Key, attr.Value, err)
+		}
+	}
+	if swap.PoolId == 0 || swap.TokenIn.Denom == "" || swap.TokenOut.Denom == "" {
+		return tokenSwap{}, fmt.Errorf("incomplete %s event", swapEvent)
+	}
+	return swap, nil
+}
+
+// handleSwaps publishes swaps of a successful transaction. blockTime is the time of the block if known,
+// otherwise the time of the latest block at the same height is used, or now if the transaction arrived first.
+func (p *Publisher) handleSwaps(result *abci.TxResult, hash string, blockTime time.Time) {
+	if result.Result.Code != 0 {
+		return
+	}
+	swaps, errs := parseSwaps(result.Result.Events)
+	for _, err := range errs {
+		p.errCounter.Add(1)
+		p.Logger.Warn("Parsing swap failed", "txID", hash, "err", err)
+	}
+	if len(swaps) == 0 {
+		return
+	}
+
+	if blockTime.IsZero() {
+		blockTime = time.Now()
+		if latest := p.latestBlock.Load(); latest != nil && latest.height == result.Height {
+			blockTime = latest.time
+		}
+	}
+
+	for _, swap := range swaps {
+		metadata := make(IBCDenomTrace)
+		metadata.Add(swap.TokenIn.Denom)
+		metadata.Add(swap.TokenOut.Denom)
+		p.getDenoms(metadata)
+
+		p.PublishWithID(
+			&pb.Swap{
+				Nonce:       p.NewNonce(),
+				Height:      result.Height,
+				Time:        timestamppb.New(blockTime),
+				TxHash:      hash,
+				Index:       swap.Index,
+				Sender:      swap.Sender,
+				PoolId:      swap.PoolId,
+				TokenIn:     &pb.Coin{Denom: swap.TokenIn.Denom, Amount: swap.TokenIn.Amount.String()},
+				TokenOut:    &pb.Coin{Denom: swap.TokenOut.Denom, Amount: swap.TokenOut.Amount.String()},
+				TokenInUsd:  p.coinValueAt(blockTime, swap.TokenIn, metadata),
+				TokenOutUsd: p.coinValueAt(blockTime, swap.TokenOut, metadata),
+				Metadata:    metadataToProtobuf(metadata),
+			},
+			swapMsgID(hash, swap.Index),
+			StreamSwaps,
+		)
+		p.swapCounter.Add(1)
+		p.messagesCounter.Add(1)
+	}
+}
+
+// coinValueAt estimates the USD value of coin from the price feed. IBC denoms without a price of their own
+// are valued with the price of their base denom. Zero is returned when there is no price close to timestamp.
+func (p *Publisher) coinValueAt(timestamp time.Time, coin cosmotypes.Coin, metadata IBCDenomTrace) float64 {
+	price, estimationError, ok := p.indexer.PriceAt(timestamp, coin.Denom)
+	if trace, found := metadata[coin.Denom]; !ok && found && trace.BaseDenom != "" {
+		price, estimationError, ok = p.indexer.PriceAt(timestamp, trace.BaseDenom)
+	}
+	if !ok || absDuration(estimationError) > swapMaxPriceAge {
+		return 0
+	}
+	value, _ := new(big.Float).Mul(new(big.Float).SetInt(coin.Amount.BigInt()), big.NewFloat(price)).Float64()
+	return value
+}
@@ -0,0 +1,123 @@
+package osmosis
+
+import (
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cosmotypes "github.com/cosmos/cosmos-sdk/types"
+	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/dtlWithSocket"
+	"github.com/synternet/osmosis-publisher/pkg/indexer"
+	"github.com/synternet/osmosis-publisher/pkg/repository"
+)
+
+func swapEventOf(attrs ...string) abci.Event {
+	event := abci.Event{Type: swapEvent}
+	for i := 0; i < len(attrs); i += 2 {
+		event.Attributes = append(event.Attributes, abci.EventAttribute{Key: attrs[i], Value: attrs[i+1]})
+	}
+	return event
+}
+
+func Test_parseSwaps(t *testing.T) {
+	events := []abci.Event{
+		{Type: "message", Attributes: []abci.EventAttribute{{Key: "sender", Value: "osmo1a"}}},
+		swapEventOf("module", "gamm", "sender", "osmo1a", "pool_id", "1", "tokens_in", "100uosmo", "tokens_out", "50ibc/B"),
+		swapEventOf("module", "gamm", "sender", "osmo1a", "pool_id", "2", "tokens_in", "50ibc/B", "tokens_out", "7uion"),
+		swapEventOf("sender", "osmo1a", "pool_id", "x", "tokens_in", "1uosmo", "tokens_out", "1uion"),
+		swapEventOf("sender", "osmo1a", "pool_id", "3"),
+	}
+
+	swaps, errs := parseSwaps(events)
+	if len(errs) != 2 {
+		t.Errorf("errors = %v, want 2", errs)
+	}
+	if len(swaps) != 2 {
+		t.Fatalf("swaps = %v, want 2", swaps)
+	}
+	if s := swaps[0]; s.Index != 0 || s.Sender != "osmo1a" || s.PoolId != 1 || s.TokenIn.String() != "100uosmo" || s.TokenOut.String() != "50ibc/B" {
+		t.Errorf("unexpected first swap %+v", s)
+	}
+	if s := swaps[1]; s.Index != 1 || s.PoolId != 2 || s.TokenIn.Denom != "ibc/B" || !s.TokenOut.Amount.Equal(cosmotypes.NewInt(7)) {
+		t.Errorf("unexpected second swap %+v", s)
+	}
+}
+
+type swapIndexer struct {
+	indexer.Indexer
+	prices map[string]float64
+	age    time.Duration
+}
+
+func (i swapIndexer) PriceAt(timestamp time.Time, denom string) (float64, time.Duration, bool) {
+	price, ok := i.prices[denom]
+	return price, i.age, ok
+}
+
+func TestPublisher_coinValueAt(t *testing.T) {
+	p := &Publisher{indexer: swapIndexer{prices: map[string]float64{"uosmo": 0.5, "uatom": 2e-6}, age: -time.Minute}}
+	metadata := IBCDenomTrace{"ibc/B": ibctypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"}}
+	now := time.Now()
+
+	if got := p.coinValueAt(now, cosmotypes.NewInt64Coin("uosmo", 10), metadata); got != 5 {
+		t.Errorf("uosmo value = %v, want 5", got)
+	}
+	if got := p.coinValueAt(now, cosmotypes.NewInt64Coin("ibc/B", 1000000), metadata); got != 2 {
+		t.Errorf("ibc/B value = %v, want 2", got)
+	}
+	if got := p.coinValueAt(now, cosmotypes.NewInt64Coin("uion", 10), metadata); got != 0 {
+		t.Errorf("uion value = %v, want 0", got)
+	}
+
+	p.indexer = swapIndexer{prices: map[string]float64{"uosmo": 0.5}, age: 2 * swapMaxPriceAge}
+	if got := p.coinValueAt(now, cosmotypes.NewInt64Coin("uosmo", 10), metadata); got != 0 {
+		t.Errorf("value with a stale price = %v, want 0", got)
+	}
+}
+
+type checkpointRepository struct {
+	repository.Repository
+	checkpoints map[string]uint64
+}
+
+func (r checkpointRepository) Checkpoint(subject string) (uint64, bool) {
+	height, ok := r.checkpoints[subject]
+	return height, ok
+}
+
+func (r checkpointRepository) SaveCheckpoint(subject string, height uint64) error {
+	r.checkpoints[subject] = height
+	return nil
+}
+
+func TestPublisher_checkpointsSwapsOnly(t *testing.T) {
+	var opt options.Options
+	err := opt.Parse(WithStream(StreamBlock, false), WithStream(StreamTx, false), WithStream(StreamSwaps, true))
+	if err != nil {
+		t.Fatalf("opt.Parse failed: %v", err)
+	}
+	db := checkpointRepository{checkpoints: map[string]uint64{checkpointBlocks: 90, checkpointTxs: 100}}
+	p := &Publisher{Service: dtlWithSocket.NewNatsAndSocketConn(), db: db}
+	p.Options = opt
+
+	p.restoreCheckpoints()
+	if got := p.txDoneHeight.Load(); got != 100 {
+		t.Errorf("restored tx height = %d, want 100", got)
+	}
+	if got := p.publishedHeight.Load(); got != 0 {
+		t.Errorf("restored block height = %d, want 0 with the block stream disabled", got)
+	}
+
+	p.txDoneHeight.Store(105)
+	if err := p.saveCheckpoints(); err != nil {
+		t.Fatalf("saveCheckpoints failed: %v", err)
+	}
+	if got := db.checkpoints[checkpointTxs]; got != 105 {
+		t.Errorf("saved tx checkpoint = %d, want 105", got)
+	}
+	if got := db.checkpoints[checkpointBlocks]; got != 90 {
+		t.Errorf("saved block checkpoint = %d, want 90", got)
+	}
+}
@@ -3,8 +3,11 @@ package osmosis
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	abci "github.com/cometbft/cometbft/abci/types"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	tmtypes "github.com/cometbft/cometbft/types"
 )
@@ -14,8 +17,9 @@ func (p *Publisher) subscribeTransactions() error {
 }
 
 func (p *Publisher) handleTransactions(events <-chan ctypes.ResultEvent) error {
-	sentinel := p.MakeSentinel(time.Minute * 10)
-
+	if !p.waitCaughtUp() {
+		return nil
+	}
 	for {
 		select {
 		case <-p.Context.Done():
@@ -27,13 +31,9 @@ func (p *Publisher) handleTransactions(events <-chan ctypes.ResultEvent) error {
 				return nil
 			}
 
-			if err := sentinel(); err != nil {
-				return err
-			}
-
 			switch data := ev.Data.(type) {
 			case tmtypes.EventDataTx:
-				p.handleTransaction(data, len(events))
+				p.handleLiveTransaction(data, len(events))
 			default:
 				p.evtOtherCounter.Add(1)
 			}
@@ -41,17 +41,219 @@ func (p *Publisher) handleTransactions(events <-chan ctypes.ResultEvent) error {
 	}
 }
 
-func (p *Publisher) handleTransaction(data tmtypes.EventDataTx, queueSize int) {
-	p.txCounter.Add(1)
-	p.transactionsCounter.Add(1)
+// handleLiveTransaction publishes a live transaction once the transactions of all the lower heights are published,
+// so that backfilled and live transactions go out in height order. If the lower heights can not be backfilled,
+// the transaction is left to the backfill of its own height.
+func (p *Publisher) handleLiveTransaction(data tmtypes.EventDataTx, queueSize int) {
+	p.txMu.Lock()
+	defer p.txMu.Unlock()
+	if err := p.completeTxs(data.Height); err != nil {
+		p.errCounter.Add(1)
+		p.Logger.Error("Backfilling transactions failed, deferring the transaction", "height", data.Height, "err", err)
+		return
+	}
+	p.handleTransaction(data, queueSize, time.Time{})
+}
+
+// handleTransaction publishes the transaction and its swaps. blockTime is zero for live transactions.
+func (p *Publisher) handleTransaction(data tmtypes.EventDataTx, queueSize int, blockTime time.Time) {
 	txData := data.GetTx()
 	hash := hex.EncodeToString(tmtypes.Tx(txData).Hash())
+	if !p.publishedTxs.add(hash) {
+		p.Logger.Debug("Skipping already published transaction", "txID", hash, "height", data.Height)
+		return
+	}
+
+	p.txCounter.Add(1)
+	p.txHeights.published(data.Height)
+	p.transactionsCounter.Add(1)
+	if p.StreamEnabled(StreamSwaps) {
+		p.handleSwaps(&data.TxResult, hash, blockTime)
+	}
+	if !p.StreamEnabled(StreamTx) {
+		return
+	}
+
 	tx := p.rpc.translateTransaction(txData, hash, p.NewNonce(), &data.TxResult, &data.TxResult.Result.Code)
-	p.Publish(
-		tx,
-		"tx",
-	)
+	names := extractTxMessageNames(tx)
+	mode := p.TxSubjects()
+	if mode != TxSubjectsTypes {
+		p.PublishWithID(
+			tx,
+			txMsgID(hash),
+			"tx",
+		)
+		p.messagesCounter.Add(1)
+	}
+	if mode != TxSubjectsSingle {
+		for _, tokens := range txTypeSubjects(names) {
+			p.PublishWithID(
+				tx,
+				txMsgID(hash)+"."+strings.Join(tokens, "."),
+				append([]string{"tx"}, tokens...)...,
+			)
+			p.messagesCounter.Add(1)
+		}
+	}
+
+	p.Logger.Debug("Transaction", "txID", tx.TxID, "names", names, "queue_size", queueSize)
+}
+
+// txTypeSubjects returns subject tokens of every distinct message type of the transaction, e.g.
+// [osmosis poolmanager v1beta1 MsgSwapExactAmountIn] for /osmosis.poolmanager.v1beta1.MsgSwapExactAmountIn.
+// A transaction with several messages of the same type is published once for that type,
+// and a transaction without known message types is published with the unknown type.
+func txTypeSubjects(typeUrls []string) [][]string {
+	seen := make(map[string]struct{}, len(typeUrls))
+	subjects := make([][]string, 0, len(typeUrls))
+	for _, typeUrl := range typeUrls {
+		name := strings.TrimPrefix(typeUrl[strings.LastIndex(typeUrl, "/")+1:], ".")
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
 
-	p.messagesCounter.Add(1)
-	p.Logger.Debug("Transaction", "txID", tx.TxID, "names", extractTxMessageNames(tx), "queue_size", queueSize)
+		tokens := strings.Split(name, ".")
+		for i, token := range tokens {
+			tokens[i] = sanitizeSubjectToken(token)
+		}
+		subjects = append(subjects, tokens)
+	}
+	if len(subjects) == 0 {
+		subjects = append(subjects, []string{txTypeUnknown})
+	}
+	return subjects
+}
+
+// sanitizeSubjectToken replaces characters that are not allowed in a NATS subject token.
+func sanitizeSubjectToken(token string) string {
+	if token == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n', '*', '>':
+			return '_'
+		}
+		return r
+	}, token)
+}
+
+// txHeights tracks how many transactions of the recent heights were published. The transaction
+// counts of the heights come from the live blocks, so that a height is complete once all of its
+// transactions were published, without waiting for the transactions of the next height.
+type txHeights struct {
+	mu     sync.Mutex
+	counts map[int64]int
+	seen   map[int64]int
+}
+
+func newTxHeights() *txHeights {
+	return &txHeights{
+		counts: make(map[int64]int),
+		seen:   make(map[int64]int),
+	}
+}
+
+// setCount records the number of transactions of the block at height.
+func (t *txHeights) setCount(height int64, count int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.counts[height] = count
+}
+
+// published counts a published transaction of height.
+func (t *txHeights) published(height int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seen[height]++
+}
+
+// complete reports whether all the transactions of the block at height were published.
+// Heights without a live block are never complete.
+func (t *txHeights) complete(height int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	count, ok := t.counts[height]
+	return ok && t.seen[height] >= count
+}
+
+// forget drops the heights up to height.
+func (t *txHeights) forget(height int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for h := range t.counts {
+		if h <= height {
+			delete(t.counts, h)
+		}
+	}
+	for h := range t.seen {
+		if h <= height {
+			delete(t.seen, h)
+		}
+	}
+}
+
+// completeTxs publishes the transactions of the heights before height that were not delivered live
+// and marks the heights done. Without a checkpoint, the heights before height are only marked done.
+// txMu must be held.
+func (p *Publisher) completeTxs(height int64) error {
+	done := int64(p.txDoneHeight.Load())
+	if done == 0 {
+		p.txDoneHeight.Store(uint64(max(height-1, 0)))
+		p.txHeights.forget(height - 1)
+		return nil
+	}
+
+	from := done + 1
+	if maxLag := int64(p.MaxCatchupBlocks()); height-from > maxLag {
+		p.Logger.Warn("Too many heights with missed transactions, backfilling only the latest", "from", from, "to", height-1, "max", maxLag)
+		from = height - maxLag
+	}
+	for h := from; h < height; h++ {
+		if !p.txHeights.complete(h) {
+			if err := p.backfillTxs(h); err != nil {
+				return fmt.Errorf("height %d: %w", h, err)
+			}
+		}
+		p.txDoneHeight.Store(uint64(h))
+		p.txHeights.forget(h)
+	}
+	return nil
+}
+
+// backfillTxs fetches the transactions of a historical block together with their results and publishes them.
+// Transactions that were already published are skipped.
+func (p *Publisher) backfillTxs(height int64) error {
+	block, err := p.blocks.BlockAt(height)
+	if err != nil {
+		return fmt.Errorf("failed fetching block: %w", err)
+	}
+	results, err := p.blocks.BlockResultsAt(height)
+	if err != nil {
+		return fmt.Errorf("failed fetching block results: %w", err)
+	}
+	if len(results.TxsResults) != len(block.Txs) {
+		return fmt.Errorf("block has %d txs, but %d results", len(block.Txs), len(results.TxsResults))
+	}
+
+	p.Logger.Info("Transactions BACKFILL", "height", height, "txs", len(block.Txs))
+	for i, tx := range block.Txs {
+		p.handleTransaction(
+			tmtypes.EventDataTx{
+				TxResult: abci.TxResult{
+					Height: height,
+					Index:  uint32(i),
+					Tx:     tx,
+					Result: *results.TxsResults[i],
+				},
+			},
+			0,
+			block.Time,
+		)
+	}
+	return nil
 }
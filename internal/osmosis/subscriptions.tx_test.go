@@ -0,0 +1,42 @@
+package osmosis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_txTypeSubjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeUrls []string
+		want     [][]string
+	}{
+		{
+			"single",
+			[]string{"/osmosis.poolmanager.v1beta1.MsgSwapExactAmountIn"},
+			[][]string{{"osmosis", "poolmanager", "v1beta1", "MsgSwapExactAmountIn"}},
+		},
+		{
+			"distinct types",
+			[]string{"/ibc.applications.transfer.v1.MsgTransfer", "/osmosis.poolmanager.v1beta1.MsgSwapExactAmountIn", "/ibc.applications.transfer.v1.MsgTransfer"},
+			[][]string{{"ibc", "applications", "transfer", "v1", "MsgTransfer"}, {"osmosis", "poolmanager", "v1beta1", "MsgSwapExactAmountIn"}},
+		},
+		{
+			"unknown",
+			nil,
+			[][]string{{txTypeUnknown}},
+		},
+		{
+			"sanitized",
+			[]string{"/a.b*c.>"},
+			[][]string{{"a", "b_c", "_"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := txTypeSubjects(tt.typeUrls); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("txTypeSubjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
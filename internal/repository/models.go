@@ -21,6 +21,13 @@ type TokenPrice struct {
 	Base        string `gorm:"index:idx_token_price,unique"`
 }
 
+type Checkpoint struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Subject   string `gorm:"index:idx_checkpoint,unique"`
+	Height    uint64
+}
+
 type Pool struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
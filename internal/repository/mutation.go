@@ -42,6 +42,15 @@ func (r *Repository) SavePool(pool repository.Pool) error {
 	return result.Error
 }
 
+func (r *Repository) SaveCheckpoint(subject string, height uint64) error {
+	checkpoint := Checkpoint{
+		Subject: subject,
+		Height:  height,
+	}
+	result := r.dbCon.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "subject"}}, DoUpdates: clause.AssignmentColumns([]string{"height", "updated_at"})}).Model(&Checkpoint{}).Create(&checkpoint)
+	return result.Error
+}
+
 // PruneTokenPrices will remove all token prices prior timestamp.
 func (r *Repository) PruneTokenPrices(timestamp time.Time) (int, error) {
 	result := r.dbCon.Model(&TokenPrice{}).Delete(&TokenPrice{}, "last_updated < ?", timestamp.UnixNano())
@@ -8,13 +8,14 @@ import (
 	IBCTypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
 	_ "github.com/lib/pq"
 	"github.com/synternet/osmosis-publisher/pkg/repository"
+	"gorm.io/gorm"
 )
 
 func (r *Repository) IBCDenom(ibc string) (IBCTypes.DenomTrace, bool) {
 	var denom IBCDenom
 	result := r.dbCon.Model(&IBCDenom{}).Limit(1).Find(&denom, "ibc = ?", ibc)
 	if result.Error != nil {
-		r.logger.Error("Error fetching IBC Denom from DB:", result.Error)
+		r.logger.Error("Error fetching IBC Denom from DB", "err", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return IBCTypes.DenomTrace{}, false
@@ -29,7 +30,7 @@ func (r *Repository) IBCDenomAll() []IBCTypes.DenomTrace {
 	var denoms []IBCDenom
 	result := r.dbCon.Model(&IBCDenom{}).Find(&denoms)
 	if result.Error != nil {
-		r.logger.Error("Error fetching all IBC Denoms from DB:", result.Error)
+		r.logger.Error("Error fetching all IBC Denoms from DB", "err", result.Error)
 		return nil
 	}
 
@@ -48,7 +49,7 @@ func (r *Repository) TokenPrice(timestamp time.Time, denom string) (repository.T
 	var token TokenPrice
 	result := r.dbCon.Model(&TokenPrice{}).Limit(1).Find(&token, "last_updated = ? AND name = ?", timestamp.UnixNano(), denom)
 	if result.Error != nil {
-		r.logger.Error("Error fetching TokenPrice from DB:", result.Error)
+		r.logger.Error("Error fetching TokenPrice from DB", "err", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return repository.TokenPrice{}, false
@@ -80,7 +81,7 @@ func (r *Repository) NearestTokenPrice(timestamp time.Time, denom string) ([]rep
 		denom, ts, ts).Scan(&tokens)
 
 	if result.Error != nil {
-		r.logger.Error("Error fetching TokenPrices from DB:", result.Error)
+		r.logger.Error("Error fetching TokenPrices from DB", "err", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return nil, false
@@ -104,7 +105,7 @@ func (r *Repository) LatestTokenPrice(denom string) (repository.TokenPrice, bool
 	var token TokenPrice
 	result := r.dbCon.Model(&TokenPrice{}).Order("last_updated DESC").Limit(1).Find(&token, "name = ?", denom)
 	if result.Error != nil {
-		r.logger.Error("Error fetching TokenPrice from DB:", result.Error)
+		r.logger.Error("Error fetching TokenPrice from DB", "err", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return repository.TokenPrice{}, false
@@ -122,7 +123,7 @@ func (r *Repository) LatestPool(id uint64) (repository.Pool, bool) {
 	var pool Pool
 	result := r.dbCon.Model(&Pool{}).Last(&pool, "pool_id = ?", id)
 	if result.Error != nil {
-		r.logger.Error("Error fetching Pool from DB:", result.Error)
+		r.logger.Error("Error fetching Pool from DB", "err", result.Error)
 		return repository.Pool{}, false
 	}
 	if result.RowsAffected == 0 {
@@ -155,10 +156,38 @@ func (r *Repository) PoolsRange(min, max, poolId uint64) ([]repository.Pool, err
 	}
 	result := r.dbCon.Model(&Pool{}).Find(&pools, query, min, max, poolId)
 	if result.Error != nil {
-		r.logger.Error("Error fetching Pools from DB:", result.Error)
+		r.logger.Error("Error fetching Pools from DB", "err", result.Error)
 		return nil, result.Error
 	}
+	return r.pools(pools)
+}
+
+// PoolsPage will return up to limit pools from min to max height ordered by height after skipping offset,
+// and the number of pools from min to max height
+func (r *Repository) PoolsPage(min, max, poolId uint64, offset, limit int) ([]repository.Pool, int, error) {
+	db := r.dbCon.Model(&Pool{}).Where("height >= ? AND height <= ?", min, max)
+	if poolId != 0 {
+		db = db.Where("pool_id = ?", poolId)
+	}
+	// The session lets the statement be reused after Count.
+	db = db.Session(&gorm.Session{})
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		r.logger.Error("Error counting Pools in DB", "err", result.Error)
+		return nil, 0, result.Error
+	}
 
+	var pools []Pool
+	result := db.Order("height, pool_id").Offset(offset).Limit(limit).Find(&pools)
+	if result.Error != nil {
+		r.logger.Error("Error fetching Pools from DB", "err", result.Error)
+		return nil, 0, result.Error
+	}
+	ret, err := r.pools(pools)
+	return ret, int(total), err
+}
+
+func (r *Repository) pools(pools []Pool) ([]repository.Pool, error) {
 	ret := make([]repository.Pool, len(pools))
 	for i, p := range pools {
 		liquidity, err := sdk.ParseCoinsNormalized(p.Liquidity)
@@ -182,6 +211,19 @@ func (r *Repository) PoolsRange(min, max, poolId uint64) ([]repository.Pool, err
 	return ret, nil
 }
 
+// Checkpoint will return the last height published on the subject
+func (r *Repository) Checkpoint(subject string) (uint64, bool) {
+	var checkpoint Checkpoint
+	result := r.dbCon.Model(&Checkpoint{}).Limit(1).Find(&checkpoint, "subject = ?", subject)
+	if result.Error != nil {
+		r.logger.Error("Error fetching Checkpoint from DB", "err", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, false
+	}
+	return checkpoint.Height, true
+}
+
 func (r *Repository) TokenPricesRange(min, max time.Time, denom string) ([]repository.TokenPrice, error) {
 	var prices []TokenPrice
 	query := "last_updated >= ? AND last_updated <= ? AND name = ?"
@@ -190,10 +232,36 @@ func (r *Repository) TokenPricesRange(min, max time.Time, denom string) ([]repos
 	}
 	result := r.dbCon.Model(&TokenPrice{}).Find(&prices, query, min.UnixNano(), max.UnixNano(), denom)
 	if result.Error != nil {
-		r.logger.Error("Error fetching Token Prices from DB:", result.Error)
+		r.logger.Error("Error fetching Token Prices from DB", "err", result.Error)
 		return nil, result.Error
 	}
+	return tokenPrices(prices), nil
+}
+
+// TokenPricesPage will return up to limit token prices between min and max timestamps ordered by time after skipping offset,
+// and the number of token prices between min and max timestamps
+func (r *Repository) TokenPricesPage(min, max time.Time, denom string, offset, limit int) ([]repository.TokenPrice, int, error) {
+	db := r.dbCon.Model(&TokenPrice{}).Where("last_updated >= ? AND last_updated <= ?", min.UnixNano(), max.UnixNano())
+	if denom != "" {
+		db = db.Where("name = ?", denom)
+	}
+	db = db.Session(&gorm.Session{})
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		r.logger.Error("Error counting Token Prices in DB", "err", result.Error)
+		return nil, 0, result.Error
+	}
 
+	var prices []TokenPrice
+	result := db.Order("last_updated, name").Offset(offset).Limit(limit).Find(&prices)
+	if result.Error != nil {
+		r.logger.Error("Error fetching Token Prices from DB", "err", result.Error)
+		return nil, 0, result.Error
+	}
+	return tokenPrices(prices), int(total), nil
+}
+
+func tokenPrices(prices []TokenPrice) []repository.TokenPrice {
 	ret := make([]repository.TokenPrice, len(prices))
 	for i, p := range prices {
 		ret[i] = repository.TokenPrice{
@@ -204,5 +272,5 @@ func (r *Repository) TokenPricesRange(min, max time.Time, denom string) ([]repos
 		}
 	}
 
-	return ret, nil
+	return ret
 }
@@ -35,6 +35,10 @@ func New(db *gorm.DB, logger *slog.Logger) (*Repository, error) {
 	if err != nil {
 		return nil, fmt.Errorf("TokenPrice migrate error: %w", err)
 	}
+	err = db.AutoMigrate(&Checkpoint{})
+	if err != nil {
+		return nil, fmt.Errorf("Checkpoint migrate error: %w", err)
+	}
 	return ret, nil
 }
 
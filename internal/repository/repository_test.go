@@ -280,6 +280,26 @@ func TestRepository_TokenPrice(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "page",
+			f: func(db *repository.Repository, t *testing.T) error {
+				prices, total, err := db.TokenPricesPage(time.Unix(TimestampBaseOsmo, 0).Add(time.Second), time.Unix(TimestampBaseOsmo, 0).Add(time.Second*5), "", 1, 2)
+				if err != nil {
+					return fmt.Errorf("TokenPricesPage failed: %w", err)
+				}
+				if total != 4 {
+					return fmt.Errorf("total = %d, want 4", total)
+				}
+				if len(prices) != 2 || prices[0].Name != "OSMO" || prices[1].Name != "ATOM" {
+					return fmt.Errorf("wrong records: %v", prices)
+				}
+				if prices[0].LastUpdated.Compare(time.Unix(TimestampBaseOsmo, 0).Add(time.Second)) != 0 || prices[1].LastUpdated.Compare(time.Unix(TimestampBaseOsmo, 0).Add(time.Second*2)) != 0 {
+					return fmt.Errorf("wrong records: %v", prices)
+				}
+				return nil
+			},
+			wantErr: false,
+		},
 		{
 			name: "nearest last",
 			f: func(db *repository.Repository, t *testing.T) error {
@@ -431,6 +451,23 @@ func TestRepository_Pools(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "page",
+			f: func(db *repository.Repository, t *testing.T) error {
+				pools, total, err := db.PoolsPage(1, 3, 1, 1, 1)
+				if err != nil {
+					return fmt.Errorf("PoolsPage failed: %w", err)
+				}
+				if total != 3 {
+					return fmt.Errorf("total = %d, want 3", total)
+				}
+				if len(pools) != 1 || pools[0].Height != 2 {
+					return fmt.Errorf("wrong records: %v", pools)
+				}
+				return nil
+			},
+			wantErr: false,
+		},
 		{
 			name: "404 pool",
 			f: func(db *repository.Repository, t *testing.T) error {
@@ -509,3 +546,67 @@ func TestRepository_Pools(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_Checkpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       func(db *repository.Repository, t *testing.T) error
+		wantErr bool
+	}{
+		{
+			name: "404",
+			f: func(db *repository.Repository, t *testing.T) error {
+				height, found := db.Checkpoint("unknown")
+				if found {
+					return fmt.Errorf("found %d", height)
+				}
+				return nil
+			},
+			wantErr: false,
+		},
+		{
+			name: "save",
+			f: func(db *repository.Repository, t *testing.T) error {
+				if err := db.SaveCheckpoint("block", 100); err != nil {
+					return err
+				}
+				height, found := db.Checkpoint("block")
+				if !found {
+					return fmt.Errorf("Did not find")
+				}
+				if height != 100 {
+					return fmt.Errorf("wrong height: %d", height)
+				}
+				return nil
+			},
+			wantErr: false,
+		},
+		{
+			name: "update",
+			f: func(db *repository.Repository, t *testing.T) error {
+				if err := db.SaveCheckpoint("tx", 100); err != nil {
+					return err
+				}
+				if err := db.SaveCheckpoint("tx", 101); err != nil {
+					return err
+				}
+				height, _ := db.Checkpoint("tx")
+				if height != 101 {
+					return fmt.Errorf("wrong height: %d", height)
+				}
+				return nil
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := makeDB()
+
+			err := tt.f(db, t)
+			if (tt.wantErr && err == nil) || (!tt.wantErr && err != nil) {
+				t.Errorf("Checkpoint test wantErr = %v, err %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,22 +1,35 @@
 package dtlWithSocket
 
 import (
-	"encoding/json"
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"log"
-	"net"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/synternet/data-layer-sdk/pkg/options"
 	"github.com/synternet/data-layer-sdk/pkg/service"
+	"github.com/synternet/osmosis-publisher/pkg/payload"
 
 	"google.golang.org/protobuf/proto"
 )
 
 type Service struct {
 	*service.Service
-	Socket     *net.Conn
-	SocketAddr string
+	sinks     []sink
+	sinkCodec options.Codec
+	outbox    *outboxConn
+	jetStream bool
+	js        *jetStreamConn
+
+	// JetStream mode publishes through its own queue, so that messages carry their ids.
+	queue         chan *nats.Msg
+	published     atomic.Uint64
+	publishErrors atomic.Uint64
 }
 
 // NewNatsAndSocketConn initializes a new Service with default settings
@@ -26,97 +39,301 @@ func NewNatsAndSocketConn() *Service {
 	}
 }
 
-// Close closes both NATS and socket connections
+// Close closes NATS connections. Sinks are closed once the service context is done.
 func (n *Service) Close() {
+	if n.js != nil && !n.js.wait(jetStreamCloseTimeout) {
+		n.Logger.Warn("Closing with unacknowledged JetStream messages", "pending", n.js.js.PublishAsyncPending())
+	}
 	if n.Service != nil {
 		n.Service.Close()
 	}
-	n.closeUnixSocket()
+	if n.outbox != nil {
+		if err := n.outbox.outbox.Close(); err != nil {
+			n.Logger.Error("Closing outbox failed", "err", err)
+		}
+	}
 }
 
-// InitUnixSocket initializes a Unix socket connection
-func (n *Service) InitUnixSocket() error {
-	// Ensure previous socket connection is closed
-	n.closeUnixSocket()
-
-	if n.SocketAddr == "" {
-		return fmt.Errorf("socket address is empty")
+// WithPubSocket mirrors transactions to the Unix socket at socketAddr.
+// It is a shorthand of a SinkUnix sink with the "tx" subject filter, see WithSocketSubjects.
+func WithPubSocket(socketAddr string) options.Option {
+	return func(o *options.Options) {
+		o.Params["SocketAddr"] = socketAddr
 	}
+}
 
-	conn, err := net.Dial("unix", n.SocketAddr)
-	if err != nil {
-		return fmt.Errorf("failed to open Unix socket: %w", err)
+// WithSocketSubjects sets the subject filters of WithPubSocket. Defaults to "tx".
+func WithSocketSubjects(subjects ...string) options.Option {
+	return func(o *options.Options) {
+		o.Params["SocketSubjects"] = subjects
 	}
-	n.Socket = &conn
+}
 
-	return nil
+// WithSocketListen makes WithPubSocket listen on the socket and accept many clients
+// instead of connecting to it. See SinkListen.
+func WithSocketListen(enabled bool) options.Option {
+	return func(o *options.Options) {
+		o.Params["SocketListen"] = enabled
+	}
 }
 
-// closeUnixSocket closes the Unix socket connection
-func (n *Service) closeUnixSocket() {
-	if n.Socket != nil && *n.Socket != nil {
-		(*n.Socket).Close()
-		n.Socket = nil
+// WithSocketFraming sets the framing of WithPubSocket. Defaults to FramingLength.
+func WithSocketFraming(framing string) options.Option {
+	return func(o *options.Options) {
+		o.Params["SocketFraming"] = framing
 	}
 }
 
-// PublishToUnixSocket publishes a message to the Unix socket
-func (n *Service) PublishToUnixSocket(msg any) {
-	if n.Socket == nil || *n.Socket == nil {
-		if err := n.InitUnixSocket(); err != nil {
-			log.Printf("Could not initialize Unix socket: %v", err)
-			return
-		}
+// WithSinkCodec sets the codec of the message in FramingProtobuf envelopes.
+// Defaults to the codec of the service.
+func WithSinkCodec(codec options.Codec) options.Option {
+	return func(o *options.Options) {
+		o.Params["SinkCodec"] = codec
 	}
+}
 
-	data, err := json.Marshal(msg)
-	if err != nil {
-		log.Printf("Error serializing message: %v", err)
-		return
+// WithSinks mirrors published messages that match the subject filters of each sink.
+func WithSinks(configs ...SinkConfig) options.Option {
+	return func(o *options.Options) {
+		sinks, _ := o.Params["Sinks"].([]SinkConfig)
+		o.Params["Sinks"] = append(sinks, configs...)
 	}
+}
 
-	// Prefix with the length
-	lengthPrefix := fmt.Sprintf("%010d", len(data))
-	fullMessage := append([]byte(lengthPrefix), data...)
+// WithOutbox records messages in an on-disk outbox in dir while the publishing NATS connection is down
+// and replays them once it is back. maxBytes limits the outbox size and messages older than maxAge are dropped.
+func WithOutbox(dir string, maxBytes int64, maxAge time.Duration) options.Option {
+	return func(o *options.Options) {
+		o.Params["OutboxDir"] = dir
+		o.Params["OutboxMaxBytes"] = maxBytes
+		o.Params["OutboxMaxAge"] = maxAge
+	}
+}
 
-	if _, err = (*n.Socket).Write(fullMessage); err != nil {
-		log.Printf("Error writing to Unix socket: %v", err)
-		n.closeUnixSocket()
+// WithJetStream publishes messages with JetStream and waits for the stream to acknowledge them.
+// Messages published with PublishWithID carry the id in the Nats-Msg-Id header.
+func WithJetStream(enabled bool) options.Option {
+	return func(o *options.Options) {
+		o.Params["JetStream"] = enabled
 	}
 }
 
-// WithPubSocket sets up a socket connection for publishing
-func WithPubSocket(socketAddr string) options.Option {
+// WithPayload compresses published messages with encoding (see pkg/payload) and splits messages larger than
+// maxPayload bytes into chunks. Zero maxPayload uses the max payload of the NATS server.
+func WithPayload(encoding string, maxPayload int64) options.Option {
 	return func(o *options.Options) {
-		fmt.Println("SOCKET_ADDR")
-		o.Params["SocketAddr"] = socketAddr
+		o.Params["PayloadEncoding"] = encoding
+		o.Params["PayloadMaxBytes"] = maxPayload
 	}
 }
 
 // Publish will sign the message and publish it to a subject constructed from "{prefix}.{name}.{suffixes}".
-// Publish will use PubNats And Socket connection.
+// Publish will use PubNats and the sinks that match the subject.
 func (n *Service) Publish(msg proto.Message, suffixes ...string) error {
+	return n.PublishWithID(msg, "", suffixes...)
+}
+
+// PublishWithID is the same as Publish, but in JetStream mode the message is published with msgID in
+// the Nats-Msg-Id header, so that the stream drops duplicates of the same content. Empty msgID publishes without an id.
+// In JetStream mode messages are signed and queued by the Service itself, since the publish queue
+// of the embedded service.Service can not carry headers.
+func (n *Service) PublishWithID(msg proto.Message, msgID string, suffixes ...string) error {
 	subject := n.Service.Subject(suffixes...)
-	if strings.HasSuffix(subject, ".tx") {
-		n.PublishToUnixSocket(msg)
+	n.publishToSinks(msg, msgID, subject, strings.Join(suffixes, "."))
+	if !n.jetStream {
+		return n.Service.PublishTo(msg, subject)
+	}
+
+	if n.PubNats == nil {
+		return service.ErrPubConnection
+	}
+	data, err := n.Codec.Encode(nil, msg)
+	if err != nil {
+		return err
+	}
+	nmsg, err := n.signMsg(data, subject)
+	if err != nil {
+		return err
+	}
+	if msgID != "" {
+		nmsg.Header.Set(nats.MsgIdHdr, msgID)
+	}
+	select {
+	case <-n.Context.Done():
+		return n.Context.Err()
+	case n.queue <- nmsg:
+	}
+	return nil
+}
+
+// signMsg creates a message with the identity, signature and timestamp headers the service sets on published messages.
+// It signs the messages that are not published through the service, which does not expose its signing.
+func (n *Service) signMsg(payload []byte, subject string) (*nats.Msg, error) {
+	signature, _, err := n.Sign(payload)
+	if err != nil {
+		return nil, err
 	}
-	return n.Service.PublishTo(msg, subject)
+
+	return &nats.Msg{
+		Subject: subject,
+		Data:    payload,
+		Header: nats.Header{
+			"identity":  {n.Identity},
+			"signature": {base64.StdEncoding.EncodeToString(signature)},
+			"timestamp": {strconv.FormatInt(time.Now().UnixNano(), 10)},
+		},
+	}, nil
 }
 
-// Configure overrides the service's Configure method to initialize the socket if specified
+// publishToSinks queues the message to every sink whose filters match the subject
+// without the "{prefix}.{name}." part.
+func (n *Service) publishToSinks(msg proto.Message, msgID, subject, suffix string) {
+	var m *sinkMessage
+	for _, sink := range n.sinks {
+		if !sink.Match(suffix) {
+			continue
+		}
+		if m == nil {
+			m = newSinkMessage(msg, msgID, subject, n.sinkCodec)
+		}
+		if err := sink.Enqueue(suffix, m); err != nil && !errors.Is(err, ErrSinkFull) {
+			n.Logger.Error("Mirroring message to sink failed", "sink", sink.Name(), "subject", subject, "err", err)
+		}
+	}
+}
+
+// Configure overrides the service's Configure method to set up the sinks if specified
 func (n *Service) Configure(opts ...options.Option) error {
 	if err := n.Service.Configure(opts...); err != nil {
 		return err
 	}
-	if socketAddr, ok := n.Service.Params["SocketAddr"]; ok {
-		if addr, ok := socketAddr.(string); ok && addr != "" {
-			n.SocketAddr = addr
-			if err := n.InitUnixSocket(); err != nil {
-				return fmt.Errorf("failed to initialize Unix socket: %w", err)
-			}
-			n.Service.Logger.Info("Unix socket initialized", "address", addr)
+
+	configs := options.Param(n.Service.Options, "Sinks", []SinkConfig(nil))
+	if addr := options.Param(n.Service.Options, "SocketAddr", ""); addr != "" {
+		subjects := options.Param(n.Service.Options, "SocketSubjects", []string{"tx"})
+		config := SinkConfig{Name: "socket", Type: SinkUnix, Address: addr, Subjects: subjects}
+		if options.Param(n.Service.Options, "SocketListen", false) {
+			config.Type = SinkListen
+		}
+		config.Framing = options.Param(n.Service.Options, "SocketFraming", "")
+		configs = append(configs, config)
+	}
+	n.sinkCodec = options.Param[options.Codec](n.Service.Options, "SinkCodec", n.Service.Codec)
+	names := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		sink, err := newSink(config, n.Service.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to configure sink: %w", err)
+		}
+		if names[sink.Name()] {
+			return fmt.Errorf("duplicate sink name %q", sink.Name())
+		}
+		names[sink.Name()] = true
+		n.sinks = append(n.sinks, sink)
+		n.Service.Logger.Info("Sink configured", "sink", sink.Name(), "type", config.Type, "address", config.Address, "subjects", config.Subjects)
+	}
+	if len(n.sinks) > 0 {
+		n.Service.AddStatusCallback(n.sinksStatus)
+	}
+
+	if options.Param(n.Service.Options, "JetStream", false) {
+		conn, err := newJetStreamConn(n.Service.PubNats, n.Service.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize JetStream: %w", err)
+		}
+		n.Service.PubNats = conn
+		n.jetStream = true
+		n.js = conn
+		n.Service.AddStatusCallback(conn.status)
+		n.queue = make(chan *nats.Msg, n.Service.PublishQueueSize)
+		n.Service.AddStatusCallback(n.publishQueueStatus)
+		n.Service.Logger.Info("JetStream publishing enabled")
+	}
+
+	if dir := options.Param(n.Service.Options, "OutboxDir", ""); dir != "" {
+		outbox, err := OpenOutbox(dir, options.Param(n.Service.Options, "OutboxMaxBytes", int64(0)), options.Param(n.Service.Options, "OutboxMaxAge", time.Duration(0)))
+		if err != nil {
+			return fmt.Errorf("failed to open outbox: %w", err)
+		}
+		n.outbox = &outboxConn{
+			NatsConn: n.Service.PubNats,
+			outbox:   outbox,
+		}
+		n.Service.PubNats = n.outbox
+		n.Service.AddStatusCallback(n.outboxStatus)
+		if n.js != nil {
+			n.js.fallback = outbox.Push
 		}
+		n.Service.Logger.Info("Outbox opened", "dir", dir, "pending", outbox.Len())
+	}
+
+	// The outbox is below the payload, so that it keeps the chunks that failed rather than the whole message,
+	// and chunks that were published are not published again on replay.
+	if n.Service.PubNats != nil {
+		encoding := options.Param(n.Service.Options, "PayloadEncoding", payload.EncodingNone)
+		if _, err := payload.Compress(encoding, nil); err != nil {
+			return fmt.Errorf("failed to configure payload: %w", err)
+		}
+		conn := newPayloadConn(n.Service.PubNats, encoding, options.Param(n.Service.Options, "PayloadMaxBytes", int64(0)))
+		n.Service.PubNats = conn
+		n.Service.AddStatusCallback(conn.status)
 	}
 
 	return nil
 }
+
+// Start starts the service and the sinks and replays the outbox whenever the connection is up.
+func (n *Service) Start() context.Context {
+	for _, sink := range n.sinks {
+		sink := sink
+		n.Group.Go(func() error { return sink.Run(n.Context) })
+	}
+	if n.outbox != nil {
+		n.Group.Go(n.replayOutbox)
+	}
+	if n.jetStream {
+		n.Group.Go(n.runPublishQueue)
+	}
+	return n.Service.Start()
+}
+
+func (n *Service) replayOutbox() error {
+	ticker := time.NewTicker(outboxReplayPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.Context.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		published, err := n.outbox.replay()
+		if err != nil {
+			n.Logger.Warn("Outbox replay failed", "published", published, "pending", n.outbox.outbox.Len(), "err", err)
+		} else if published > 0 {
+			n.Logger.Info("Outbox replayed", "published", published)
+		}
+	}
+}
+
+func (n *Service) outboxStatus() map[string]string {
+	outbox := n.outbox.outbox
+	return map[string]string{
+		"outbox_depth":   strconv.Itoa(outbox.Len()),
+		"outbox_bytes":   strconv.FormatInt(outbox.Size(), 10),
+		"outbox_dropped": strconv.FormatUint(outbox.dropped.Load(), 10),
+		"outbox_expired": strconv.FormatUint(outbox.expired.Load(), 10),
+	}
+}
+
+// sinksStatus merges the status of all the sinks. Status callbacks are keyed by function,
+// so that a callback per sink would replace one another.
+func (n *Service) sinksStatus() map[string]string {
+	status := make(map[string]string)
+	for _, sink := range n.sinks {
+		for k, v := range sink.status() {
+			status[k] = v
+		}
+	}
+	return status
+}
@@ -0,0 +1,130 @@
+package dtlWithSocket
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+)
+
+const (
+	// jetStreamMaxPending is the number of messages that may wait for the stream acknowledgement.
+	jetStreamMaxPending = 256
+	// jetStreamCloseTimeout is how long Close waits for the pending acknowledgements.
+	jetStreamCloseTimeout = 5 * time.Second
+)
+
+// jetStreamConn publishes messages with JetStream, so that a message is only lost if the stream did not store it.
+// Acknowledgements are awaited asynchronously with at most jetStreamMaxPending messages in flight.
+// Messages that the stream fails to acknowledge are handed to fallback, the outbox if one is configured.
+// Streams drop messages with a Nats-Msg-Id that was already seen within their duplicate window.
+type jetStreamConn struct {
+	options.NatsConn
+	js       nats.JetStreamContext
+	logger   *slog.Logger
+	fallback func(msg *nats.Msg) error
+
+	ackErrors atomic.Uint64
+	requeued  atomic.Uint64
+}
+
+func newJetStreamConn(nc options.NatsConn, logger *slog.Logger) (*jetStreamConn, error) {
+	conn, ok := nc.(interface {
+		JetStream(opts ...nats.JSOpt) (nats.JetStreamContext, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("NATS connection does not support JetStream")
+	}
+	c := &jetStreamConn{
+		NatsConn: nc,
+		logger:   logger,
+	}
+	js, err := conn.JetStream(nats.PublishAsyncMaxPending(jetStreamMaxPending), nats.PublishAsyncErrHandler(c.ackFailed))
+	if err != nil {
+		return nil, err
+	}
+	c.js = js
+	return c, nil
+}
+
+func (c *jetStreamConn) IsConnected() bool {
+	conn, ok := c.NatsConn.(interface{ IsConnected() bool })
+	return !ok || conn.IsConnected()
+}
+
+func (c *jetStreamConn) MaxPayload() int64 {
+	conn, ok := c.NatsConn.(interface{ MaxPayload() int64 })
+	if !ok {
+		return 0
+	}
+	return conn.MaxPayload()
+}
+
+// PublishMsg publishes the message without waiting for its acknowledgement.
+// It fails if the pending window stays full.
+func (c *jetStreamConn) PublishMsg(msg *nats.Msg) error {
+	_, err := c.js.PublishMsgAsync(msg)
+	return err
+}
+
+func (c *jetStreamConn) ackFailed(_ nats.JetStream, msg *nats.Msg, err error) {
+	c.ackErrors.Add(1)
+	if c.fallback != nil {
+		if ferr := c.fallback(msg); ferr == nil {
+			c.requeued.Add(1)
+			return
+		}
+	}
+	c.logger.Warn("JetStream did not acknowledge message", "subject", msg.Subject, "id", msg.Header.Get(nats.MsgIdHdr), "err", err)
+}
+
+// wait waits until all the published messages are acknowledged or the timeout passes.
+func (c *jetStreamConn) wait(timeout time.Duration) bool {
+	select {
+	case <-c.js.PublishAsyncComplete():
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (c *jetStreamConn) status() map[string]string {
+	pending := 0
+	if c.js != nil {
+		pending = c.js.PublishAsyncPending()
+	}
+	return map[string]string{
+		"jetstream_pending":    strconv.Itoa(pending),
+		"jetstream_ack_errors": strconv.FormatUint(c.ackErrors.Load(), 10),
+		"jetstream_requeued":   strconv.FormatUint(c.requeued.Load(), 10),
+	}
+}
+
+// runPublishQueue publishes the messages queued by PublishWithID in JetStream mode in order.
+func (n *Service) runPublishQueue() error {
+	for {
+		select {
+		case <-n.Context.Done():
+			return nil
+		case msg := <-n.queue:
+			if err := n.PubNats.PublishMsg(msg); err != nil {
+				n.publishErrors.Add(1)
+				n.Logger.Warn("Publishing failed", "subject", msg.Subject, "err", err)
+				continue
+			}
+			n.published.Add(1)
+		}
+	}
+}
+
+func (n *Service) publishQueueStatus() map[string]string {
+	return map[string]string{
+		"jetstream_queue":          strconv.Itoa(len(n.queue)),
+		"jetstream_published":      strconv.FormatUint(n.published.Load(), 10),
+		"jetstream_publish_errors": strconv.FormatUint(n.publishErrors.Load(), 10),
+	}
+}
@@ -0,0 +1,91 @@
+package dtlWithSocket
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/data-layer-sdk/pkg/service"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewJetStreamConn(t *testing.T) {
+	if _, err := newJetStreamConn(&fakeConn{}, slog.Default()); err == nil {
+		t.Fatalf("newJetStreamConn must fail without JetStream support")
+	}
+
+	conn := &jetStreamConn{NatsConn: &fakeConn{up: true}}
+	if !conn.IsConnected() {
+		t.Errorf("IsConnected must follow the underlying connection")
+	}
+	conn.NatsConn = &fakeConn{}
+	if conn.IsConnected() {
+		t.Errorf("IsConnected must follow the underlying connection")
+	}
+}
+
+func TestJetStreamConn_ackFailed(t *testing.T) {
+	var requeued []string
+	conn := &jetStreamConn{NatsConn: &fakeConn{up: true}, logger: slog.Default()}
+	msg := &nats.Msg{Subject: "a.b.block", Header: nats.Header{nats.MsgIdHdr: {"block-1"}}}
+
+	conn.ackFailed(nil, msg, nats.ErrTimeout)
+	conn.fallback = func(msg *nats.Msg) error {
+		requeued = append(requeued, msg.Header.Get(nats.MsgIdHdr))
+		return nil
+	}
+	conn.ackFailed(nil, msg, nats.ErrTimeout)
+	conn.fallback = func(*nats.Msg) error { return errors.New("outbox is full") }
+	conn.ackFailed(nil, msg, nats.ErrTimeout)
+
+	status := conn.status()
+	if status["jetstream_ack_errors"] != "3" || status["jetstream_requeued"] != "1" {
+		t.Errorf("status = %v, want 3 ack errors and 1 requeued", status)
+	}
+	if len(requeued) != 1 || requeued[0] != "block-1" {
+		t.Errorf("requeued = %v, want [block-1]", requeued)
+	}
+}
+
+type headerConn struct {
+	options.NatsConn
+	ids []string
+}
+
+func (c *headerConn) PublishMsg(msg *nats.Msg) error {
+	c.ids = append(c.ids, msg.Header.Get(nats.MsgIdHdr))
+	return nil
+}
+
+func TestService_PublishWithID(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nc := &headerConn{}
+	n := NewNatsAndSocketConn()
+	if err := n.Configure(func(o *options.Options) { o.PrivateKey = key }, service.WithPubNats(nc)); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	// JetStream mode without a JetStream connection.
+	n.jetStream = true
+	n.queue = make(chan *nats.Msg, 2)
+
+	n.PublishWithID(wrapperspb.String("block"), "block-1", "block")
+	n.Publish(wrapperspb.String("mempool"), "mempool")
+	for i := 0; i < 2; i++ {
+		msg := <-n.queue
+		signature, _ := base64.StdEncoding.DecodeString(msg.Header.Get("signature"))
+		if !ed25519.Verify(pub, msg.Data, signature) {
+			t.Errorf("message %s is not signed", msg.Subject)
+		}
+		n.PubNats.PublishMsg(msg)
+	}
+	if want := []string{"block-1", ""}; len(nc.ids) != 2 || nc.ids[0] != want[0] || nc.ids[1] != want[1] {
+		t.Errorf("published ids = %q, want %q", nc.ids, want)
+	}
+}
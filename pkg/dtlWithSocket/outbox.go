@@ -0,0 +1,205 @@
+package dtlWithSocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/recordlog"
+)
+
+const (
+	outboxFile         = "outbox.log"
+	outboxOffsetFile   = "outbox.offset"
+	outboxReplayPeriod = time.Second
+	outboxReplayBatch  = 256
+)
+
+var ErrOutboxFull = errors.New("outbox is full")
+
+// outboxRecord is a NATS message as stored in the outbox.
+type outboxRecord struct {
+	Subject string              `json:"subject"`
+	Header  map[string][]string `json:"header,omitempty"`
+	Data    []byte              `json:"data"`
+	Time    int64               `json:"time"`
+}
+
+// Outbox is an on-disk FIFO of NATS messages that could not be published.
+// Messages are kept in a record log whose read position is stored next to it, so that messages survive restarts.
+type Outbox struct {
+	mu     sync.Mutex
+	log    *recordlog.Log
+	maxAge time.Duration
+
+	dropped atomic.Uint64
+	expired atomic.Uint64
+}
+
+// OpenOutbox opens or creates the outbox in dir. Messages are rejected when pending messages reach maxBytes
+// and are dropped on replay when they are older than maxAge. Zero maxAge keeps messages forever.
+func OpenOutbox(dir string, maxBytes int64, maxAge time.Duration) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed creating outbox directory: %w", err)
+	}
+	log, err := recordlog.Open(filepath.Join(dir, outboxFile), filepath.Join(dir, outboxOffsetFile), maxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening outbox: %w", err)
+	}
+	return &Outbox{
+		log:    log,
+		maxAge: maxAge,
+	}, nil
+}
+
+// Len returns the number of pending messages.
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.log.Len()
+}
+
+// Size returns the size of the pending messages in bytes.
+func (o *Outbox) Size() int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.log.Size()
+}
+
+// Push appends the message to the outbox. ErrOutboxFull is returned if the size limit is reached.
+func (o *Outbox) Push(msg *nats.Msg) error {
+	record, err := json.Marshal(outboxRecord{
+		Subject: msg.Subject,
+		Header:  msg.Header,
+		Data:    msg.Data,
+		Time:    time.Now().UnixNano(),
+	})
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	err = o.log.Push(record)
+	if errors.Is(err, recordlog.ErrFull) {
+		err = ErrOutboxFull
+	}
+	if err != nil {
+		o.dropped.Add(1)
+	}
+	return err
+}
+
+// Replay publishes up to outboxReplayBatch pending messages in order and stores the read position,
+// so that a crash during a long replay publishes at most one batch again. It stops early if the outbox
+// is empty or publish fails. Messages older than the age limit are dropped. It returns the number of published messages.
+func (o *Outbox) Replay(publish func(msg *nats.Msg) error) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	published := 0
+	defer o.log.SaveOffset()
+	for i := 0; i < outboxReplayBatch && o.log.Len() > 0; i++ {
+		var record outboxRecord
+		buf, err := o.log.Peek()
+		if err == nil {
+			err = json.Unmarshal(buf, &record)
+		}
+		if err != nil {
+			o.dropped.Add(uint64(o.log.Len()))
+			o.log.Reset()
+			return published, fmt.Errorf("failed reading outbox: %w", err)
+		}
+
+		if o.maxAge <= 0 || time.Since(time.Unix(0, record.Time)) <= o.maxAge {
+			msg := &nats.Msg{
+				Subject: record.Subject,
+				Header:  record.Header,
+				Data:    record.Data,
+			}
+			if err := publish(msg); err != nil {
+				return published, err
+			}
+			published++
+		} else {
+			o.expired.Add(1)
+		}
+
+		if err := o.log.Drop(); err != nil {
+			return published, fmt.Errorf("failed removing replayed message from outbox: %w", err)
+		}
+	}
+	return published, nil
+}
+
+// Close stores the read position and closes the log.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.log.Close()
+}
+
+// outboxConn publishes directly while the connection is up and the outbox is empty.
+// Otherwise messages are recorded in the outbox, so that the order is kept.
+type outboxConn struct {
+	options.NatsConn
+	mu     sync.Mutex
+	outbox *Outbox
+}
+
+func (c *outboxConn) IsConnected() bool {
+	conn, ok := c.NatsConn.(interface{ IsConnected() bool })
+	return !ok || conn.IsConnected()
+}
+
+func (c *outboxConn) MaxPayload() int64 {
+	conn, ok := c.NatsConn.(interface{ MaxPayload() int64 })
+	if !ok {
+		return 0
+	}
+	return conn.MaxPayload()
+}
+
+func (c *outboxConn) PublishMsg(msg *nats.Msg) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.outbox.Len() == 0 && c.IsConnected() {
+		if err := c.NatsConn.PublishMsg(msg); err == nil {
+			return nil
+		}
+	}
+	return c.outbox.Push(msg)
+}
+
+// replay publishes messages from the outbox once the connection is back.
+// Live messages wait only for the current batch, and go to the outbox behind the replayed ones.
+func (c *outboxConn) replay() (int, error) {
+	published := 0
+	for {
+		n, done, err := c.replayBatch()
+		published += n
+		if err != nil || done {
+			return published, err
+		}
+	}
+}
+
+func (c *outboxConn) replayBatch() (int, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.outbox.Len() == 0 || !c.IsConnected() {
+		return 0, true, nil
+	}
+	n, err := c.outbox.Replay(c.NatsConn.PublishMsg)
+	return n, false, err
+}
@@ -0,0 +1,128 @@
+package dtlWithSocket
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+)
+
+type fakeConn struct {
+	options.NatsConn
+	up        bool
+	published []string
+}
+
+func (c *fakeConn) IsConnected() bool {
+	return c.up
+}
+
+func (c *fakeConn) PublishMsg(msg *nats.Msg) error {
+	if !c.up {
+		return nats.ErrConnectionClosed
+	}
+	c.published = append(c.published, msg.Subject)
+	return nil
+}
+
+func TestOutboxConn(t *testing.T) {
+	dir := t.TempDir()
+	outbox, err := OpenOutbox(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenOutbox failed: %v", err)
+	}
+	nc := &fakeConn{}
+	conn := &outboxConn{NatsConn: nc, outbox: outbox}
+
+	for i := 0; i < 3; i++ {
+		conn.PublishMsg(&nats.Msg{Subject: fmt.Sprintf("s%d", i), Header: nats.Header{"k": {"v"}}, Data: []byte("data")})
+	}
+	if outbox.Len() != 3 || len(nc.published) != 0 {
+		t.Fatalf("outbox = %d, published = %v, want 3 and none", outbox.Len(), nc.published)
+	}
+
+	// Messages must survive a restart.
+	outbox.Close()
+	outbox, err = OpenOutbox(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenOutbox failed: %v", err)
+	}
+	defer outbox.Close()
+	conn.outbox = outbox
+	if outbox.Len() != 3 {
+		t.Fatalf("reopened outbox = %d, want 3", outbox.Len())
+	}
+
+	nc.up = true
+	conn.PublishMsg(&nats.Msg{Subject: "s3"})
+	if n, err := conn.replay(); n != 4 || err != nil {
+		t.Fatalf("replay() = %d, %v, want 4", n, err)
+	}
+	conn.PublishMsg(&nats.Msg{Subject: "s4"})
+
+	want := []string{"s0", "s1", "s2", "s3", "s4"}
+	if fmt.Sprint(nc.published) != fmt.Sprint(want) {
+		t.Errorf("published = %v, want %v", nc.published, want)
+	}
+	if outbox.Size() != 0 {
+		t.Errorf("Size() = %d after replay, want 0", outbox.Size())
+	}
+}
+
+func TestOutboxLimits(t *testing.T) {
+	outbox, err := OpenOutbox(t.TempDir(), 200, time.Millisecond)
+	if err != nil {
+		t.Fatalf("OpenOutbox failed: %v", err)
+	}
+	defer outbox.Close()
+
+	if err := outbox.Push(&nats.Msg{Subject: "a", Data: make([]byte, 50)}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if err := outbox.Push(&nats.Msg{Subject: "b", Data: make([]byte, 200)}); !errors.Is(err, ErrOutboxFull) {
+		t.Fatalf("Push over limit error = %v, want ErrOutboxFull", err)
+	}
+
+	time.Sleep(time.Millisecond * 2)
+	n, err := outbox.Replay(func(msg *nats.Msg) error {
+		t.Errorf("expired message %s replayed", msg.Subject)
+		return nil
+	})
+	if n != 0 || err != nil || outbox.Len() != 0 {
+		t.Errorf("Replay() = %d, %v, len = %d, want expired message dropped", n, err, outbox.Len())
+	}
+}
+
+func TestOutboxReplayBatches(t *testing.T) {
+	dir := t.TempDir()
+	outbox, err := OpenOutbox(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenOutbox failed: %v", err)
+	}
+	defer outbox.Close()
+	for i := 0; i < outboxReplayBatch+10; i++ {
+		outbox.Push(&nats.Msg{Subject: fmt.Sprintf("s%d", i)})
+	}
+
+	if n, err := outbox.Replay(func(*nats.Msg) error { return nil }); n != outboxReplayBatch || err != nil {
+		t.Fatalf("Replay() = %d, %v, want a batch of %d", n, err, outboxReplayBatch)
+	}
+	// The read position is stored after every batch, so a crash does not replay it again.
+	crashed, err := OpenOutbox(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenOutbox failed: %v", err)
+	}
+	defer crashed.Close()
+	if crashed.Len() != 10 {
+		t.Errorf("outbox after crash = %d, want 10", crashed.Len())
+	}
+
+	nc := &fakeConn{up: true}
+	conn := &outboxConn{NatsConn: nc, outbox: outbox}
+	if n, err := conn.replay(); n != 10 || err != nil {
+		t.Errorf("replay() = %d, %v, want the remaining 10", n, err)
+	}
+}
@@ -0,0 +1,87 @@
+package dtlWithSocket
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/payload"
+)
+
+// payloadHeaderReserve is the part of the max payload left for the headers of a chunk.
+const payloadHeaderReserve = 4096
+
+// payloadConn compresses messages and splits the ones that do not fit into the max payload into chunks.
+type payloadConn struct {
+	options.NatsConn
+	encoding  string
+	chunkSize int
+
+	compressed atomic.Uint64
+	chunked    atomic.Uint64
+	chunks     atomic.Uint64
+}
+
+func newPayloadConn(nc options.NatsConn, encoding string, maxPayload int64) *payloadConn {
+	if maxPayload <= 0 {
+		if conn, ok := nc.(interface{ MaxPayload() int64 }); ok {
+			maxPayload = conn.MaxPayload()
+		}
+	}
+	chunkSize := 0
+	if maxPayload > payloadHeaderReserve {
+		chunkSize = int(maxPayload - payloadHeaderReserve)
+	}
+	return &payloadConn{
+		NatsConn:  nc,
+		encoding:  encoding,
+		chunkSize: chunkSize,
+	}
+}
+
+func (c *payloadConn) IsConnected() bool {
+	conn, ok := c.NatsConn.(interface{ IsConnected() bool })
+	return !ok || conn.IsConnected()
+}
+
+// PublishMsg publishes all the chunks of the message. Encoding is applied to a copy, so that the caller keeps the original.
+// Publishing stops at the first chunk that fails. The outbox is below this connection, so that it keeps only
+// the failed chunk and the ones after it, and the published chunks are not published again on replay.
+func (c *payloadConn) PublishMsg(msg *nats.Msg) error {
+	// Chunks and encoded messages are published as is.
+	if msg.Header.Get(payload.HeaderEncoding) != "" || msg.Header.Get(payload.HeaderChunkId) != "" {
+		return c.NatsConn.PublishMsg(msg)
+	}
+	if c.encoding != payload.EncodingNone {
+		header := make(nats.Header, len(msg.Header)+1)
+		for k, v := range msg.Header {
+			header[k] = v
+		}
+		msg = &nats.Msg{Subject: msg.Subject, Header: header, Data: msg.Data}
+		if err := payload.Encode(c.encoding, msg); err != nil {
+			return err
+		}
+		c.compressed.Add(1)
+	}
+
+	chunks := payload.Split(msg, c.chunkSize)
+	if len(chunks) > 1 {
+		c.chunked.Add(1)
+		c.chunks.Add(uint64(len(chunks)))
+	}
+	for _, chunk := range chunks {
+		if err := c.NatsConn.PublishMsg(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *payloadConn) status() map[string]string {
+	return map[string]string{
+		"payload_compressed": strconv.FormatUint(c.compressed.Load(), 10),
+		"payload_chunked":    strconv.FormatUint(c.chunked.Load(), 10),
+		"payload_chunks":     strconv.FormatUint(c.chunks.Load(), 10),
+	}
+}
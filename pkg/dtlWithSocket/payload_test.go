@@ -0,0 +1,131 @@
+package dtlWithSocket
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/payload"
+)
+
+type captureConn struct {
+	options.NatsConn
+	msgs []*nats.Msg
+}
+
+func (c *captureConn) MaxPayload() int64 {
+	return payloadHeaderReserve + 100
+}
+
+func (c *captureConn) PublishMsg(msg *nats.Msg) error {
+	c.msgs = append(c.msgs, msg)
+	return nil
+}
+
+func TestPayloadConn(t *testing.T) {
+	nc := &captureConn{}
+	conn := newPayloadConn(nc, payload.EncodingGzip, 0)
+	if conn.chunkSize != 100 {
+		t.Fatalf("chunkSize = %d, want 100", conn.chunkSize)
+	}
+
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	msg := &nats.Msg{Subject: "a.b.block", Header: nats.Header{"signature": {"sig"}}, Data: data}
+	if err := conn.PublishMsg(msg); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg.Data, data) || msg.Header.Get(payload.HeaderEncoding) != "" {
+		t.Errorf("original message must not be modified")
+	}
+	if len(nc.msgs) < 2 || conn.chunked.Load() != 1 {
+		t.Fatalf("got %d messages, want chunks", len(nc.msgs))
+	}
+
+	a := payload.NewAssembler(0)
+	var got *nats.Msg
+	for _, m := range nc.msgs {
+		var err error
+		if got, err = a.Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got == nil || !bytes.Equal(got.Data, data) {
+		t.Errorf("reassembled message differs")
+	}
+
+	// A chunk returned to the outbox is published as is.
+	chunk := nc.msgs[0]
+	if err := conn.PublishMsg(chunk); err != nil {
+		t.Fatal(err)
+	}
+	if last := nc.msgs[len(nc.msgs)-1]; last != chunk {
+		t.Errorf("replayed chunk was encoded again")
+	}
+}
+
+// flakyConn fails the publish with the given index.
+type flakyConn struct {
+	captureConn
+	publishes int
+	failAt    int
+}
+
+func (c *flakyConn) IsConnected() bool {
+	return true
+}
+
+func (c *flakyConn) PublishMsg(msg *nats.Msg) error {
+	c.publishes++
+	if c.publishes == c.failAt {
+		return nats.ErrConnectionClosed
+	}
+	return c.captureConn.PublishMsg(msg)
+}
+
+func TestPayloadConnOutbox(t *testing.T) {
+	outbox, err := OpenOutbox(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatalf("OpenOutbox failed: %v", err)
+	}
+	defer outbox.Close()
+	nc := &flakyConn{failAt: 3}
+	out := &outboxConn{NatsConn: nc, outbox: outbox}
+	conn := newPayloadConn(out, payload.EncodingNone, 0)
+	if conn.chunkSize != 100 {
+		t.Fatalf("chunkSize = %d, want the max payload of the connection below the outbox", conn.chunkSize)
+	}
+
+	msg := &nats.Msg{Subject: "a.b.block", Header: nats.Header{nats.MsgIdHdr: {"block.1"}}, Data: make([]byte, 550)}
+	if err := conn.PublishMsg(msg); err != nil {
+		t.Fatalf("PublishMsg failed: %v", err)
+	}
+	if len(nc.msgs) != 2 || outbox.Len() != 4 {
+		t.Fatalf("published %d chunks and queued %d, want 2 and 4", len(nc.msgs), outbox.Len())
+	}
+	if _, err := out.replay(); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+
+	// Every chunk is published once and in order.
+	if len(nc.msgs) != 6 {
+		t.Fatalf("published %d chunks, want 6", len(nc.msgs))
+	}
+	a := payload.NewAssembler(0)
+	var got *nats.Msg
+	for i, m := range nc.msgs {
+		if index := m.Header.Get(payload.HeaderChunkIndex); index != strconv.Itoa(i) {
+			t.Errorf("chunk %d has index %s", i, index)
+		}
+		if got, err = a.Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got == nil || !bytes.Equal(got.Data, msg.Data) {
+		t.Errorf("reassembled message differs")
+	}
+}
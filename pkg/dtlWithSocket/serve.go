@@ -0,0 +1,31 @@
+package dtlWithSocket
+
+import (
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/service"
+)
+
+// ServeQueue is the queue group of ServeBuf subscriptions, so that only one of the publisher replicas replies.
+const ServeQueue = "publisher"
+
+// ServeBuf replies to requests sent to "{prefix}.{name}.{suffixes}" with the bytes returned by handler.
+// Unlike service.Serve, it subscribes on the publishing connection, so that the consumers of the published
+// subjects can send requests. Replies are signed the same way as published messages.
+func (n *Service) ServeBuf(handler func(data []byte) []byte, suffixes ...string) (*nats.Subscription, error) {
+	if n.PubNats == nil {
+		return nil, service.ErrPubConnection
+	}
+	return n.PubNats.QueueSubscribe(n.Subject(suffixes...), ServeQueue, func(msg *nats.Msg) {
+		if msg.Reply == "" {
+			return
+		}
+		reply, err := n.signMsg(handler(msg.Data), msg.Reply)
+		if err != nil {
+			n.Logger.Error("Signing reply failed", "subject", msg.Subject, "err", err)
+			return
+		}
+		if err := msg.RespondMsg(reply); err != nil {
+			n.Logger.Error("Replying failed", "subject", msg.Subject, "err", err)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package dtlWithSocket
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"github.com/synternet/osmosis-publisher/pkg/types/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// FramingLength prefixes the JSON message with its length as 10 ASCII digits.
+	FramingLength = "length"
+	// FramingNDJSON writes a JSON envelope with the subject, the headers and the message per line.
+	FramingNDJSON = "ndjson"
+	// FramingProtobuf prefixes a protobuf pb.Envelope with its length as a 4-byte big-endian integer.
+	FramingProtobuf = "protobuf"
+
+	// HeaderTimestamp is the publishing time in Unix nanoseconds, the same as in NATS messages.
+	HeaderTimestamp = "timestamp"
+)
+
+// Framings are the framings supported by sinks.
+var Framings = []string{FramingLength, FramingNDJSON, FramingProtobuf}
+
+// ndjsonEnvelope is a line of FramingNDJSON.
+type ndjsonEnvelope struct {
+	Subject string            `json:"subject"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Data    json.RawMessage   `json:"data"`
+}
+
+// sinkMessage is a published message on its way to the sinks. Frames are cached,
+// so that sinks with the same framing encode the message once.
+type sinkMessage struct {
+	subject  string
+	headers  map[string]string
+	msg      proto.Message
+	protobuf options.Codec
+	json     []byte
+	frames   map[string][]byte
+}
+
+func newSinkMessage(msg proto.Message, msgID, subject string, protobuf options.Codec) *sinkMessage {
+	headers := map[string]string{
+		HeaderTimestamp: strconv.FormatInt(time.Now().UnixNano(), 10),
+	}
+	if msgID != "" {
+		headers[nats.MsgIdHdr] = msgID
+	}
+	return &sinkMessage{
+		subject:  subject,
+		headers:  headers,
+		msg:      msg,
+		protobuf: protobuf,
+		frames:   make(map[string][]byte),
+	}
+}
+
+// frame encodes the message with framing.
+func (m *sinkMessage) frame(framing string) ([]byte, error) {
+	if data, ok := m.frames[framing]; ok {
+		return data, nil
+	}
+
+	var data []byte
+	switch framing {
+	case FramingLength:
+		payload, err := m.marshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		data = append([]byte(fmt.Sprintf("%010d", len(payload))), payload...)
+	case FramingNDJSON:
+		payload, err := m.marshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		line, err := json.Marshal(ndjsonEnvelope{Subject: m.subject, Headers: m.headers, Data: payload})
+		if err != nil {
+			return nil, err
+		}
+		data = append(line, '\n')
+	case FramingProtobuf:
+		if m.protobuf == nil {
+			return nil, fmt.Errorf("protobuf framing requires a protobuf codec")
+		}
+		payload, err := m.protobuf.Encode(nil, m.msg)
+		if err != nil {
+			return nil, err
+		}
+		envelope, err := proto.Marshal(&pb.Envelope{Subject: m.subject, Headers: m.headers, Data: payload})
+		if err != nil {
+			return nil, err
+		}
+		data = binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(envelope)), uint32(len(envelope)))
+		data = append(data, envelope...)
+	default:
+		return nil, fmt.Errorf("unknown framing %q", framing)
+	}
+	m.frames[framing] = data
+	return data, nil
+}
+
+func (m *sinkMessage) marshalJSON() ([]byte, error) {
+	if m.json != nil {
+		return m.json, nil
+	}
+	data, err := json.Marshal(m.msg)
+	if err != nil {
+		return nil, err
+	}
+	m.json = data
+	return data, nil
+}
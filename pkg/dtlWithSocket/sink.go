@@ -0,0 +1,370 @@
+package dtlWithSocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	SinkUnix   = "unix"
+	SinkTCP    = "tcp"
+	SinkFile   = "file"
+	SinkStdout = "stdout"
+	SinkListen = "listen"
+
+	SlowClientsDrop       = "drop"
+	SlowClientsDisconnect = "disconnect"
+
+	sinkDefaultBuffer   = 1024
+	sinkDefaultMaxBytes = 100 << 20
+	sinkDefaultMaxFiles = 5
+	sinkBackoffMin      = 100 * time.Millisecond
+	sinkBackoffMax      = 30 * time.Second
+)
+
+var ErrSinkFull = errors.New("sink queue is full")
+
+// SinkTarget is the destination of a sink. Open is called when the sink starts
+// and again, with backoff, after opening or writing fails.
+type SinkTarget interface {
+	Open() (io.WriteCloser, error)
+}
+
+// SinkConfig configures a sink that receives a copy of published messages besides NATS.
+type SinkConfig struct {
+	// Name identifies the sink in logs and telemetry. Defaults to Type.
+	Name string `json:"name,omitempty"`
+	// Type is one of SinkUnix, SinkTCP, SinkFile, SinkStdout or SinkListen. Ignored when Target is set.
+	Type string `json:"type"`
+	// Address is the socket path, the host:port or the file path. SinkListen listens on the socket path.
+	Address string `json:"address,omitempty"`
+	// Subjects are NATS style filters ("*" matches a token, ">" the rest) of the subject
+	// without the "{prefix}.{name}." part. Empty filters match all the subjects.
+	Subjects []string `json:"subjects,omitempty"`
+	// Buffer is the number of messages queued while the destination is slow or unavailable.
+	// SinkListen has a queue of this size per client.
+	Buffer int `json:"buffer,omitempty"`
+	// Framing is one of FramingLength (default of socket sinks), FramingNDJSON (default of file and stdout sinks)
+	// or FramingProtobuf.
+	Framing string `json:"framing,omitempty"`
+	// SlowClients is the policy of SinkListen when the queue of a client is full:
+	// SlowClientsDrop (default) drops the message and SlowClientsDisconnect disconnects the client.
+	SlowClients string `json:"slow_clients,omitempty"`
+	// MaxBytes is the size at which a file sink is rotated.
+	MaxBytes int64 `json:"max_bytes,omitempty"`
+	// MaxFiles is the number of rotated files kept by a file sink.
+	MaxFiles int `json:"max_files,omitempty"`
+	// Target overrides the destination selected by Type.
+	Target SinkTarget `json:"-"`
+}
+
+// ParseSink parses a sink spec such as "unix:///socket/osmosis.sock?subject=tx",
+// "tcp://localhost:9000", "file:///data/osmosis.ndjson?max_bytes=1048576&max_files=3" or "stdout".
+// Query parameters are name, subject (repeated), framing, buffer, max_bytes, max_files and slow_clients.
+func ParseSink(spec string) (SinkConfig, error) {
+	if !strings.Contains(spec, "://") {
+		spec = strings.Replace(spec, "?", "://?", 1)
+		if !strings.Contains(spec, "://") {
+			spec += "://"
+		}
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return SinkConfig{}, fmt.Errorf("bad sink %q: %w", spec, err)
+	}
+
+	query := u.Query()
+	config := SinkConfig{
+		Name:        query.Get("name"),
+		Type:        u.Scheme,
+		Address:     u.Host + u.Path,
+		Subjects:    query["subject"],
+		Framing:     query.Get("framing"),
+		SlowClients: query.Get("slow_clients"),
+	}
+	if config.Buffer, err = parseSinkInt(query, "buffer"); err != nil {
+		return SinkConfig{}, err
+	}
+	maxBytes, err := parseSinkInt(query, "max_bytes")
+	if err != nil {
+		return SinkConfig{}, err
+	}
+	config.MaxBytes = int64(maxBytes)
+	if config.MaxFiles, err = parseSinkInt(query, "max_files"); err != nil {
+		return SinkConfig{}, err
+	}
+	if err := config.validate(); err != nil {
+		return SinkConfig{}, err
+	}
+	return config, nil
+}
+
+func parseSinkInt(query url.Values, key string) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("bad sink %s %q: %w", key, value, err)
+	}
+	return n, nil
+}
+
+// LoadSinkConfig reads a JSON array of sink configs from path.
+func LoadSinkConfig(path string) ([]SinkConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading sink config: %w", err)
+	}
+	var configs []SinkConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed parsing sink config: %w", err)
+	}
+	for _, config := range configs {
+		if err := config.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return configs, nil
+}
+
+func (c SinkConfig) validate() error {
+	switch c.Framing {
+	case "", FramingLength, FramingNDJSON, FramingProtobuf:
+	default:
+		return fmt.Errorf("unknown framing %q", c.Framing)
+	}
+	if c.Target != nil {
+		return nil
+	}
+	switch c.Type {
+	case SinkUnix, SinkTCP, SinkFile, SinkListen:
+		if c.Address == "" {
+			return fmt.Errorf("%s sink requires an address", c.Type)
+		}
+	case SinkStdout:
+	default:
+		return fmt.Errorf("unknown sink type %q", c.Type)
+	}
+	switch c.SlowClients {
+	case "", SlowClientsDrop, SlowClientsDisconnect:
+	default:
+		return fmt.Errorf("unknown slow clients policy %q", c.SlowClients)
+	}
+	for _, filter := range c.Subjects {
+		if filter == "" {
+			return fmt.Errorf("empty subject filter of %s sink", c.Type)
+		}
+	}
+	return nil
+}
+
+// sink is a destination of mirrored messages.
+type sink interface {
+	Name() string
+	// Match reports whether messages on subject (without the "{prefix}.{name}." part) are wanted.
+	Match(subject string) bool
+	// Enqueue queues the message published on subject. It must not block.
+	Enqueue(subject string, msg *sinkMessage) error
+	Run(ctx context.Context) error
+	status() map[string]string
+}
+
+// newSink creates a SocketServer for SinkListen and a Sink for the other types.
+func newSink(config SinkConfig, logger *slog.Logger) (sink, error) {
+	if config.Type == SinkListen && config.Target == nil {
+		return NewSocketServer(config, logger)
+	}
+	return NewSink(config, logger)
+}
+
+// Sink queues messages for a single destination and writes them from its own goroutine,
+// so that a slow or unavailable destination never blocks publishing.
+type Sink struct {
+	name     string
+	subjects []string
+	target   SinkTarget
+	framing  string
+	queue    chan []byte
+	logger   *slog.Logger
+
+	connected atomic.Bool
+	sent      atomic.Uint64
+	dropped   atomic.Uint64
+	errors    atomic.Uint64
+}
+
+// NewSink creates a sink from config. Messages are not written until Run is called.
+func NewSink(config SinkConfig, logger *slog.Logger) (*Sink, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	target := config.Target
+	framing := FramingLength
+	switch {
+	case target != nil:
+	case config.Type == SinkUnix || config.Type == SinkTCP:
+		target = dialTarget{network: config.Type, address: config.Address}
+	case config.Type == SinkFile:
+		maxBytes, maxFiles := config.MaxBytes, config.MaxFiles
+		if maxBytes <= 0 {
+			maxBytes = sinkDefaultMaxBytes
+		}
+		if maxFiles <= 0 {
+			maxFiles = sinkDefaultMaxFiles
+		}
+		target = fileTarget{path: config.Address, maxBytes: maxBytes, maxFiles: maxFiles}
+		framing = FramingNDJSON
+	case config.Type == SinkStdout:
+		target = stdoutTarget{}
+		framing = FramingNDJSON
+	default:
+		return nil, fmt.Errorf("%s sink requires a target", config.Type)
+	}
+
+	name := config.Name
+	if name == "" {
+		name = config.Type
+	}
+	if config.Framing != "" {
+		framing = config.Framing
+	}
+	buffer := config.Buffer
+	if buffer <= 0 {
+		buffer = sinkDefaultBuffer
+	}
+	return &Sink{
+		name:     name,
+		subjects: config.Subjects,
+		target:   target,
+		framing:  framing,
+		queue:    make(chan []byte, buffer),
+		logger:   logger.With("sink", name),
+	}, nil
+}
+
+// Name returns the name of the sink.
+func (s *Sink) Name() string {
+	return s.name
+}
+
+// Match reports whether subject (without the "{prefix}.{name}." part) passes the subject filters.
+func (s *Sink) Match(subject string) bool {
+	return matchSubjects(s.subjects, subject)
+}
+
+// Enqueue queues the framed message. The message is dropped if the queue is full.
+func (s *Sink) Enqueue(subject string, msg *sinkMessage) error {
+	data, err := msg.frame(s.framing)
+	if err != nil {
+		s.errors.Add(1)
+		return err
+	}
+	select {
+	case s.queue <- data:
+		return nil
+	default:
+		s.dropped.Add(1)
+		return ErrSinkFull
+	}
+}
+
+// Run writes queued messages until ctx is done. The destination is reopened with exponential backoff
+// after a failure. Meanwhile messages stay in the queue until it is full.
+func (s *Sink) Run(ctx context.Context) error {
+	var w io.WriteCloser
+	defer func() {
+		if w != nil {
+			w.Close()
+		}
+		s.connected.Store(false)
+	}()
+
+	backoff := sinkBackoffMin
+	for {
+		if w == nil {
+			conn, err := s.target.Open()
+			if err != nil {
+				s.errors.Add(1)
+				s.logger.Warn("Opening sink failed", "err", err, "retry_in", backoff)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(backoff):
+				}
+				backoff = min(backoff*2, sinkBackoffMax)
+				continue
+			}
+			w = conn
+			backoff = sinkBackoffMin
+			s.connected.Store(true)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case data := <-s.queue:
+			if _, err := w.Write(data); err != nil {
+				s.errors.Add(1)
+				s.dropped.Add(1)
+				s.logger.Warn("Writing to sink failed", "err", err)
+				w.Close()
+				w = nil
+				s.connected.Store(false)
+				continue
+			}
+			s.sent.Add(1)
+		}
+	}
+}
+
+func (s *Sink) status() map[string]string {
+	prefix := "sink." + s.name + "."
+	return map[string]string{
+		prefix + "connected": strconv.FormatBool(s.connected.Load()),
+		prefix + "queued":    strconv.Itoa(len(s.queue)),
+		prefix + "sent":      strconv.FormatUint(s.sent.Load(), 10),
+		prefix + "dropped":   strconv.FormatUint(s.dropped.Load(), 10),
+		prefix + "errors":    strconv.FormatUint(s.errors.Load(), 10),
+	}
+}
+
+// matchSubjects reports whether subject matches any of the filters. Empty filters match all the subjects.
+func matchSubjects(filters []string, subject string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if matchSubject(filter, subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSubject matches subject against a NATS style filter.
+func matchSubject(filter, subject string) bool {
+	filterTokens := strings.Split(filter, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range filterTokens {
+		if token == ">" {
+			return i < len(subjectTokens)
+		}
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+	return len(filterTokens) == len(subjectTokens)
+}
@@ -0,0 +1,142 @@
+package dtlWithSocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/synternet/osmosis-publisher/pkg/types/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxFrameSize is the largest message accepted by Reader.
+const MaxFrameSize = 64 << 20
+
+var ErrFrameTooLarge = errors.New("frame is too large")
+
+// Envelope is a message read from a sink.
+type Envelope struct {
+	// Subject is the full NATS subject of the message. Empty with FramingLength.
+	Subject string
+	// Headers are empty with FramingLength.
+	Headers map[string]string
+	// Data is the JSON message, or the protobuf message with FramingProtobuf.
+	Data []byte
+}
+
+// Reader decodes messages written by a sink, e.g. read from a socket or a file.
+type Reader struct {
+	r       *bufio.Reader
+	framing string
+}
+
+// NewReader creates a reader of messages written with framing.
+func NewReader(r io.Reader, framing string) (*Reader, error) {
+	switch framing {
+	case FramingLength, FramingNDJSON, FramingProtobuf:
+	default:
+		return nil, fmt.Errorf("unknown framing %q", framing)
+	}
+	return &Reader{
+		r:       bufio.NewReader(r),
+		framing: framing,
+	}, nil
+}
+
+// Read returns the next message. It returns io.EOF when the stream ends between messages
+// and io.ErrUnexpectedEOF when it ends in the middle of a message.
+func (r *Reader) Read() (*Envelope, error) {
+	switch r.framing {
+	case FramingLength:
+		return r.readLength()
+	case FramingNDJSON:
+		return r.readNDJSON()
+	default:
+		return r.readProtobuf()
+	}
+}
+
+func (r *Reader) readLength() (*Envelope, error) {
+	var prefix [10]byte
+	if _, err := io.ReadFull(r.r, prefix[:]); err != nil {
+		return nil, err
+	}
+	length, err := strconv.ParseUint(string(prefix[:]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bad length prefix %q: %w", prefix, err)
+	}
+	data, err := r.readFrame(length)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{Data: data}, nil
+}
+
+func (r *Reader) readNDJSON() (*Envelope, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.r.ReadLine()
+		if err == io.EOF && len(line) > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > MaxFrameSize {
+			return nil, ErrFrameTooLarge
+		}
+		if !isPrefix {
+			break
+		}
+	}
+
+	var envelope ndjsonEnvelope
+	if err := json.Unmarshal(line, &envelope); err != nil {
+		return nil, fmt.Errorf("bad envelope: %w", err)
+	}
+	return &Envelope{
+		Subject: envelope.Subject,
+		Headers: envelope.Headers,
+		Data:    envelope.Data,
+	}, nil
+}
+
+func (r *Reader) readProtobuf() (*Envelope, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r.r, prefix[:]); err != nil {
+		return nil, err
+	}
+	data, err := r.readFrame(uint64(binary.BigEndian.Uint32(prefix[:])))
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope pb.Envelope
+	if err := proto.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("bad envelope: %w", err)
+	}
+	return &Envelope{
+		Subject: envelope.Subject,
+		Headers: envelope.Headers,
+		Data:    envelope.Data,
+	}, nil
+}
+
+func (r *Reader) readFrame(length uint64) ([]byte, error) {
+	if length > MaxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,97 @@
+package dtlWithSocket
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type protoCodec struct{}
+
+func (protoCodec) Encode(buf []byte, msg proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{}.MarshalAppend(buf, msg)
+}
+
+func (protoCodec) Decode(buf []byte, msg proto.Message) error {
+	return proto.Unmarshal(buf, msg)
+}
+
+func TestReader(t *testing.T) {
+	for _, framing := range Framings {
+		t.Run(framing, func(t *testing.T) {
+			var buf bytes.Buffer
+			for _, value := range []string{"first", "second"} {
+				msg := newSinkMessage(wrapperspb.String(value), "tx."+value, "synternet.osmosis.tx", protoCodec{})
+				data, err := msg.frame(framing)
+				if err != nil {
+					t.Fatalf("frame failed: %v", err)
+				}
+				buf.Write(data)
+			}
+
+			reader, err := NewReader(&buf, framing)
+			if err != nil {
+				t.Fatalf("NewReader failed: %v", err)
+			}
+			for _, value := range []string{"first", "second"} {
+				envelope, err := reader.Read()
+				if err != nil {
+					t.Fatalf("Read failed: %v", err)
+				}
+
+				got, want := string(envelope.Data), `{"value":"`+value+`"}`
+				if framing == FramingProtobuf {
+					decoded := &wrapperspb.StringValue{}
+					if err := proto.Unmarshal(envelope.Data, decoded); err != nil {
+						t.Fatalf("Unmarshal failed: %v", err)
+					}
+					got, want = decoded.Value, value
+				}
+				if got != want {
+					t.Errorf("Data = %q, want %q", got, want)
+				}
+
+				if framing == FramingLength {
+					if envelope.Subject != "" || envelope.Headers != nil {
+						t.Errorf("length framing must not carry subject and headers: %+v", envelope)
+					}
+					continue
+				}
+				if envelope.Subject != "synternet.osmosis.tx" || envelope.Headers[nats.MsgIdHdr] != "tx."+value || envelope.Headers[HeaderTimestamp] == "" {
+					t.Errorf("Subject = %q, Headers = %v", envelope.Subject, envelope.Headers)
+				}
+			}
+
+			if _, err := reader.Read(); err != io.EOF {
+				t.Errorf("Read() at the end = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestReader_truncated(t *testing.T) {
+	for _, framing := range Framings {
+		msg := newSinkMessage(wrapperspb.String("value"), "", "synternet.osmosis.tx", protoCodec{})
+		data, err := msg.frame(framing)
+		if err != nil {
+			t.Fatalf("frame failed: %v", err)
+		}
+
+		reader, _ := NewReader(bytes.NewReader(data[:len(data)/2]), framing)
+		if _, err := reader.Read(); err == nil {
+			t.Errorf("%s: Read() of a truncated message must fail", framing)
+		}
+	}
+}
+
+func TestReader_tooLarge(t *testing.T) {
+	reader, _ := NewReader(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}), FramingProtobuf)
+	if _, err := reader.Read(); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("Read() = %v, want ErrFrameTooLarge", err)
+	}
+}
@@ -0,0 +1,285 @@
+package dtlWithSocket
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// socketFilterTimeout is how long a new client is given to send its subject filters
+// before it starts receiving messages with the default filters.
+const socketFilterTimeout = time.Second
+
+// sinkRecord is a framed message waiting in a client queue.
+type sinkRecord struct {
+	subject string
+	data    []byte
+}
+
+// SocketServer listens on a Unix socket and mirrors messages to every connected client.
+// After connecting, a client may send a line with subject filters separated by spaces, e.g. "tx.> block\n".
+// Clients that do not send filters within a second get messages matching the filters of the sink.
+// A line can be sent at any time to replace the filters. Closing the connection, or only its
+// writing side, disconnects the client. Each client has its own queue, and
+// the SlowClients policy decides what happens when it is full.
+type SocketServer struct {
+	name       string
+	address    string
+	subjects   []string
+	buffer     int
+	disconnect bool
+	framing    string
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	clients map[*socketClient]struct{}
+
+	accepted     atomic.Uint64
+	sent         atomic.Uint64
+	dropped      atomic.Uint64
+	disconnected atomic.Uint64
+}
+
+// NewSocketServer creates a SinkListen sink. The socket is not created until Run is called.
+func NewSocketServer(config SinkConfig, logger *slog.Logger) (*SocketServer, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	name := config.Name
+	if name == "" {
+		name = SinkListen
+	}
+	framing := config.Framing
+	if framing == "" {
+		framing = FramingLength
+	}
+	buffer := config.Buffer
+	if buffer <= 0 {
+		buffer = sinkDefaultBuffer
+	}
+	return &SocketServer{
+		name:       name,
+		address:    config.Address,
+		subjects:   config.Subjects,
+		buffer:     buffer,
+		disconnect: config.SlowClients == SlowClientsDisconnect,
+		framing:    framing,
+		logger:     logger.With("sink", name),
+		clients:    make(map[*socketClient]struct{}),
+	}, nil
+}
+
+// Name returns the name of the sink.
+func (s *SocketServer) Name() string {
+	return s.name
+}
+
+// Match reports whether any client is connected, since clients choose their own filters.
+func (s *SocketServer) Match(subject string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients) > 0
+}
+
+// Enqueue queues the framed message to every client whose filters match subject.
+func (s *SocketServer) Enqueue(subject string, msg *sinkMessage) error {
+	record := sinkRecord{subject: subject}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client := range s.clients {
+		if client.isReady() && !client.match(subject) {
+			continue
+		}
+		if record.data == nil {
+			data, err := msg.frame(s.framing)
+			if err != nil {
+				return err
+			}
+			record.data = data
+		}
+		select {
+		case client.queue <- record:
+			continue
+		default:
+		}
+		if !s.disconnect {
+			s.dropped.Add(1)
+			continue
+		}
+		s.disconnected.Add(1)
+		s.logger.Warn("Disconnecting slow client", "queued", len(client.queue))
+		s.removeLocked(client)
+	}
+	return nil
+}
+
+// Run listens on the socket until ctx is done. A stale socket file left by a previous run is replaced.
+func (s *SocketServer) Run(ctx context.Context) error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Listening on Unix socket", "address", s.address)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	defer func() {
+		s.mu.Lock()
+		for client := range s.clients {
+			s.removeLocked(client)
+		}
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed accepting socket client: %w", err)
+		}
+		s.accepted.Add(1)
+		client := s.newClient(conn)
+		s.mu.Lock()
+		s.clients[client] = struct{}{}
+		s.mu.Unlock()
+		go s.readFilters(client)
+		go s.serve(client)
+	}
+}
+
+func (s *SocketServer) listen() (net.Listener, error) {
+	if info, err := os.Stat(s.address); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", s.address); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", s.address)
+		}
+		os.Remove(s.address)
+	}
+	listener, err := net.Listen("unix", s.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on Unix socket: %w", err)
+	}
+	return listener, nil
+}
+
+// serve writes the queue of the client until it is disconnected.
+func (s *SocketServer) serve(client *socketClient) {
+	defer func() {
+		s.mu.Lock()
+		s.removeLocked(client)
+		s.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(socketFilterTimeout)
+	defer timer.Stop()
+	select {
+	case <-client.done:
+		return
+	case <-client.ready:
+	case <-timer.C:
+		client.setFilters(nil)
+	}
+
+	for {
+		select {
+		case <-client.done:
+			return
+		case record := <-client.queue:
+			if !client.match(record.subject) {
+				continue
+			}
+			if _, err := client.conn.Write(record.data); err != nil {
+				s.logger.Debug("Socket client disconnected", "err", err)
+				return
+			}
+			s.sent.Add(1)
+		}
+	}
+}
+
+// readFilters reads the filter lines sent by the client. The client is removed once its side of the
+// connection is closed, since a write to it may never fail if nothing is published for it.
+func (s *SocketServer) readFilters(client *socketClient) {
+	scanner := bufio.NewScanner(client.conn)
+	for scanner.Scan() {
+		client.setFilters(strings.Fields(strings.ReplaceAll(scanner.Text(), ",", " ")))
+	}
+	s.logger.Debug("Socket client disconnected", "err", scanner.Err())
+	s.mu.Lock()
+	s.removeLocked(client)
+	s.mu.Unlock()
+}
+
+func (s *SocketServer) removeLocked(client *socketClient) {
+	if _, ok := s.clients[client]; !ok {
+		return
+	}
+	delete(s.clients, client)
+	close(client.done)
+	client.conn.Close()
+}
+
+func (s *SocketServer) status() map[string]string {
+	s.mu.Lock()
+	clients := len(s.clients)
+	s.mu.Unlock()
+	prefix := "sink." + s.name + "."
+	return map[string]string{
+		prefix + "clients":      strconv.Itoa(clients),
+		prefix + "accepted":     strconv.FormatUint(s.accepted.Load(), 10),
+		prefix + "sent":         strconv.FormatUint(s.sent.Load(), 10),
+		prefix + "dropped":      strconv.FormatUint(s.dropped.Load(), 10),
+		prefix + "disconnected": strconv.FormatUint(s.disconnected.Load(), 10),
+	}
+}
+
+// socketClient is a connection accepted by SocketServer.
+type socketClient struct {
+	conn     net.Conn
+	defaults []string
+	queue    chan sinkRecord
+	filters  atomic.Pointer[[]string]
+	ready    chan struct{}
+	once     sync.Once
+	done     chan struct{}
+}
+
+func (s *SocketServer) newClient(conn net.Conn) *socketClient {
+	return &socketClient{
+		conn:     conn,
+		defaults: s.subjects,
+		queue:    make(chan sinkRecord, s.buffer),
+		ready:    make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// setFilters replaces the filters of the client. Empty filters select the filters of the sink.
+func (c *socketClient) setFilters(filters []string) {
+	if len(filters) == 0 {
+		filters = c.defaults
+	}
+	c.filters.Store(&filters)
+	c.once.Do(func() { close(c.ready) })
+}
+
+func (c *socketClient) isReady() bool {
+	return c.filters.Load() != nil
+}
+
+func (c *socketClient) match(subject string) bool {
+	filters := c.filters.Load()
+	return filters != nil && matchSubjects(*filters, subject)
+}
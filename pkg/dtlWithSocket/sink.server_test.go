@@ -0,0 +1,144 @@
+package dtlWithSocket
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func startSocketServer(t *testing.T, config SinkConfig) *SocketServer {
+	t.Helper()
+	// Unix socket paths are limited to ~100 bytes, t.TempDir() may be too long.
+	dir, err := os.MkdirTemp("", "sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	config.Type = SinkListen
+	config.Address = filepath.Join(dir, "osmosis.sock")
+	server, err := NewSocketServer(config, slog.Default())
+	if err != nil {
+		t.Fatalf("NewSocketServer failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- server.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	})
+	return server
+}
+
+func dialSocketServer(t *testing.T, server *SocketServer, clients int) net.Conn {
+	t.Helper()
+	var conn net.Conn
+	var err error
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if conn, err = net.Dial("unix", server.address); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	for server.status()["sink."+server.name+".clients"] != strconv.Itoa(clients) {
+		if time.Now().After(deadline) {
+			t.Fatalf("client was not accepted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return conn
+}
+
+func readFrame(t *testing.T, r io.Reader) string {
+	t.Helper()
+	reader, err := NewReader(r, FramingLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := reader.Read()
+	if err != nil {
+		t.Fatalf("reading frame failed: %v", err)
+	}
+	return string(envelope.Data)
+}
+
+func TestSocketServer(t *testing.T) {
+	server := startSocketServer(t, SinkConfig{Subjects: []string{"tx"}})
+	if server.Match("tx") {
+		t.Fatal("server without clients must not match")
+	}
+
+	blocks := dialSocketServer(t, server, 1)
+	blocks.Write([]byte("block\n"))
+	defaults := dialSocketServer(t, server, 2)
+	defaults.Write([]byte("\n"))
+	time.Sleep(50 * time.Millisecond)
+
+	for _, subject := range []string{"tx", "block"} {
+		server.Enqueue(subject, newSinkMessage(wrapperspb.String(subject), "", "synternet.osmosis."+subject, nil))
+	}
+
+	blocks.SetReadDeadline(time.Now().Add(time.Second))
+	if got := readFrame(t, blocks); got != `{"value":"block"}` {
+		t.Errorf("block client got %s", got)
+	}
+	defaults.SetReadDeadline(time.Now().Add(time.Second))
+	if got := readFrame(t, defaults); got != `{"value":"tx"}` {
+		t.Errorf("default client got %s", got)
+	}
+}
+
+func TestSocketServer_slowClients(t *testing.T) {
+	for _, policy := range []string{SlowClientsDrop, SlowClientsDisconnect} {
+		t.Run(policy, func(t *testing.T) {
+			server := startSocketServer(t, SinkConfig{Buffer: 1, SlowClients: policy})
+			// The client does not send filters, so nothing is written during the first second.
+			dialSocketServer(t, server, 1)
+
+			msg := newSinkMessage(wrapperspb.String("tx"), "", "synternet.osmosis.tx", nil)
+			server.Enqueue("tx", msg)
+			server.Enqueue("tx", msg)
+
+			status := server.status()
+			if policy == SlowClientsDrop && (status["sink.listen.dropped"] != "1" || status["sink.listen.clients"] != "1") {
+				t.Errorf("status = %v, want one client and one dropped message", status)
+			}
+			if policy == SlowClientsDisconnect && (status["sink.listen.disconnected"] != "1" || status["sink.listen.clients"] != "0") {
+				t.Errorf("status = %v, want the client disconnected", status)
+			}
+		})
+	}
+}
+
+func TestSocketServer_closedClient(t *testing.T) {
+	server := startSocketServer(t, SinkConfig{Subjects: []string{"block"}})
+	conn := dialSocketServer(t, server, 1)
+	conn.Close()
+
+	// Nothing is published on the filtered subjects, so only the closed connection tells the client is gone.
+	deadline := time.Now().Add(time.Second)
+	for server.Match("tx") {
+		if time.Now().After(deadline) {
+			t.Fatalf("status = %v, want the closed client removed", server.status())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
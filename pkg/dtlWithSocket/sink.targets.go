@@ -0,0 +1,109 @@
+package dtlWithSocket
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const sinkDialTimeout = 5 * time.Second
+
+// dialTarget connects to a Unix or TCP socket.
+type dialTarget struct {
+	network string
+	address string
+}
+
+func (t dialTarget) Open() (io.WriteCloser, error) {
+	conn, err := net.DialTimeout(t.network, t.address, sinkDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s socket %s: %w", t.network, t.address, err)
+	}
+	return conn, nil
+}
+
+// stdoutTarget writes to the standard output.
+type stdoutTarget struct{}
+
+func (stdoutTarget) Open() (io.WriteCloser, error) {
+	return nopCloser{os.Stdout}, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// fileTarget appends to a file that is rotated when it reaches maxBytes.
+type fileTarget struct {
+	path     string
+	maxBytes int64
+	maxFiles int
+}
+
+func (t fileTarget) Open() (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed creating sink directory: %w", err)
+	}
+	f := &rotatingFile{fileTarget: t}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// rotatingFile renames the file to path.1, path.1 to path.2 and so on when the next write would exceed maxBytes.
+// Only maxFiles rotated files are kept. Every write is kept whole in a single file.
+type rotatingFile struct {
+	fileTarget
+	file *os.File
+	size int64
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed opening sink file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed opening sink file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) Write(data []byte) (int, error) {
+	if f.size > 0 && f.size+int64(len(data)) > f.maxBytes {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed closing sink file: %w", err)
+	}
+	os.Remove(fmt.Sprintf("%s.%d", f.path, f.maxFiles))
+	for i := f.maxFiles - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return fmt.Errorf("failed rotating sink file: %w", err)
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) Close() error {
+	return f.file.Close()
+}
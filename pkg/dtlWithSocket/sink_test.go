@@ -0,0 +1,213 @@
+package dtlWithSocket
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/synternet/data-layer-sdk/pkg/options"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestParseSink(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    SinkConfig
+		wantErr bool
+	}{
+		{"unix:///socket/osmosis.sock?subject=tx", SinkConfig{Type: SinkUnix, Address: "/socket/osmosis.sock", Subjects: []string{"tx"}}, false},
+		{"tcp://localhost:9000?name=feed&buffer=10&subject=tx.>&subject=block", SinkConfig{Name: "feed", Type: SinkTCP, Address: "localhost:9000", Subjects: []string{"tx.>", "block"}, Buffer: 10}, false},
+		{"file:///data/osmosis.ndjson?max_bytes=1024&max_files=2", SinkConfig{Type: SinkFile, Address: "/data/osmosis.ndjson", MaxBytes: 1024, MaxFiles: 2}, false},
+		{"stdout", SinkConfig{Type: SinkStdout}, false},
+		{"stdout?subject=block", SinkConfig{Type: SinkStdout, Subjects: []string{"block"}}, false},
+		{"tcp://", SinkConfig{}, true},
+		{"kafka://localhost:9092", SinkConfig{}, true},
+		{"listen:///socket/osmosis.sock?framing=ndjson&slow_clients=disconnect", SinkConfig{Type: SinkListen, Address: "/socket/osmosis.sock", Framing: FramingNDJSON, SlowClients: SlowClientsDisconnect}, false},
+		{"stdout?buffer=many", SinkConfig{}, true},
+		{"stdout?framing=xml", SinkConfig{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSink(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSink() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSinkConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sinks.json")
+	os.WriteFile(path, []byte(`[{"name":"wasm","type":"unix","address":"/socket/osmosis.sock","subjects":["tx"]},{"type":"stdout"}]`), 0o644)
+
+	got, err := LoadSinkConfig(path)
+	if err != nil {
+		t.Fatalf("LoadSinkConfig failed: %v", err)
+	}
+	want := []SinkConfig{
+		{Name: "wasm", Type: SinkUnix, Address: "/socket/osmosis.sock", Subjects: []string{"tx"}},
+		{Type: SinkStdout},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadSinkConfig() = %+v, want %+v", got, want)
+	}
+
+	os.WriteFile(path, []byte(`[{"type":"file"}]`), 0o644)
+	if _, err := LoadSinkConfig(path); err == nil {
+		t.Error("LoadSinkConfig() of a file sink without address must fail")
+	}
+}
+
+func Test_matchSubject(t *testing.T) {
+	tests := []struct {
+		filter  string
+		subject string
+		want    bool
+	}{
+		{"tx", "tx", true},
+		{"tx", "tx.swap", false},
+		{"tx.*", "tx.swap", true},
+		{"tx.*", "tx", false},
+		{"tx.>", "tx.swap.exact", true},
+		{"tx.>", "tx", false},
+		{">", "state.pools", true},
+		{"*.pool.*", "volume.pool.1", true},
+		{"*.pool.*", "volume.pools.1", false},
+	}
+	for _, tt := range tests {
+		if got := matchSubject(tt.filter, tt.subject); got != tt.want {
+			t.Errorf("matchSubject(%q, %q) = %v, want %v", tt.filter, tt.subject, got, tt.want)
+		}
+	}
+}
+
+type bufferTarget struct {
+	mu    sync.Mutex
+	fails int
+	buf   bytes.Buffer
+}
+
+func (t *bufferTarget) Open() (io.WriteCloser, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.fails > 0 {
+		t.fails--
+		return nil, errors.New("unavailable")
+	}
+	return nopCloser{t}, nil
+}
+
+func (t *bufferTarget) Write(data []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buf.Write(data)
+}
+
+func (t *bufferTarget) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buf.String()
+}
+
+func TestSink(t *testing.T) {
+	target := &bufferTarget{fails: 1}
+	sink, err := NewSink(SinkConfig{Name: "test", Subjects: []string{"tx"}, Buffer: 2, Target: target}, slog.Default())
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	if !sink.Match("tx") || sink.Match("block") {
+		t.Fatal("sink must match tx only")
+	}
+
+	// Messages are kept in the queue while the target is unavailable.
+	for _, value := range []string{"1", "2", "3"} {
+		sink.Enqueue("tx", newSinkMessage(wrapperspb.String(value), "", "synternet.osmosis.tx", nil))
+	}
+	if sink.dropped.Load() != 1 {
+		t.Fatalf("dropped = %d, want 1", sink.dropped.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sink.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	want := `0000000013{"value":"1"}0000000013{"value":"2"}`
+	deadline := time.Now().Add(time.Second)
+	for target.String() != want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := target.String(); got != want {
+		t.Fatalf("written = %q, want %q", got, want)
+	}
+	if sink.sent.Load() != 2 || sink.errors.Load() != 1 {
+		t.Errorf("sent = %d, errors = %d, want 2 and 1", sink.sent.Load(), sink.errors.Load())
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "osmosis.ndjson")
+	w, err := fileTarget{path: path, maxBytes: 10, maxFiles: 2}.Open()
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	w.Close()
+
+	for name, want := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q (%v), want %q", filepath.Base(name), got, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("only 2 rotated files must be kept: %v", err)
+	}
+}
+
+func TestConfigure_socketSubjects(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withKey := func(o *options.Options) { o.PrivateKey = key }
+	addr := filepath.Join(t.TempDir(), "osmosis.sock")
+
+	n := NewNatsAndSocketConn()
+	if err := n.Configure(withKey, WithPubSocket(addr)); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	if len(n.sinks) != 1 || !n.sinks[0].Match("tx") || n.sinks[0].Match("tx.cosmos.bank.v1beta1.MsgSend") {
+		t.Errorf("socket must mirror the tx subject only")
+	}
+
+	n = NewNatsAndSocketConn()
+	if err := n.Configure(withKey, WithPubSocket(addr), WithSocketSubjects("tx.>")); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	if len(n.sinks) != 1 || n.sinks[0].Match("tx") || !n.sinks[0].Match("tx.cosmos.bank.v1beta1.MsgSend") {
+		t.Errorf("socket must mirror the message type subjects")
+	}
+}
@@ -1,12 +1,16 @@
 package indexer
 
 import (
+	"errors"
 	"time"
 
 	ibctypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
 	"github.com/synternet/osmosis-publisher/pkg/types"
 )
 
+// ErrHeightNotIndexed is returned for heights outside of the heights the indexer keeps.
+var ErrHeightNotIndexed = errors.New("height is not indexed")
+
 type Indexer interface {
 	// DenomTrace returns IBC Denom trace when IBC denom is provided (in the form `ibc/<hash>`)
 	DenomTrace(ibc string) (ibctypes.DenomTrace, error)
@@ -17,9 +21,17 @@ type Indexer interface {
 	// SetLatestPrice should be called every time a new price quote is received from price feed
 	SetLatestPrice(token, base string, value float64, lastUpdated time.Time) error
 
+	// PriceAt estimates the price of denom at timestamp from the cached price feed and returns the estimation error.
+	// ok is false when there are no cached prices of denom.
+	PriceAt(timestamp time.Time, denom string) (value float64, estimationError time.Duration, ok bool)
+
 	// PoolStatusesAt returns poolStatuses for a specific height given pool IDs
 	PoolStatusesAt(height uint64, poolId ...uint64) ([]types.PoolStatus, uint64, error)
 
+	// QueryPoolStatusesAt is the same as PoolStatusesAt, but pools fetched from the node are neither cached nor stored.
+	// ErrHeightNotIndexed is returned for heights outside of the indexed window.
+	QueryPoolStatusesAt(height uint64, poolId ...uint64) ([]types.PoolStatus, uint64, error)
+
 	// CalculateVolumes will modify poolStatuses in-place by calculating USD prices of volumes
 	//
 	// NOTE: volume will have two prices: actual price and price difference
@@ -0,0 +1,136 @@
+package payload
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type partial struct {
+	chunks   [][]byte
+	received int
+	size     int
+	first    time.Time
+}
+
+// Assembler reassembles chunked messages. It is safe for concurrent use.
+type Assembler struct {
+	mu       sync.Mutex
+	partials map[string]*partial
+	maxAge   time.Duration
+}
+
+// NewAssembler creates an assembler that drops incomplete messages older than maxAge.
+// Zero maxAge keeps incomplete messages forever.
+func NewAssembler(maxAge time.Duration) *Assembler {
+	return &Assembler{
+		partials: make(map[string]*partial),
+		maxAge:   maxAge,
+	}
+}
+
+// Add accepts a received message. It returns the complete decompressed message, or nil
+// if more chunks are needed. Messages that are not chunked are only decompressed into a copy,
+// msg itself is never modified. Duplicate chunks are ignored.
+func (a *Assembler) Add(msg *nats.Msg) (*nats.Msg, error) {
+	id := msg.Header.Get(HeaderChunkId)
+	if id == "" {
+		if msg.Header.Get(HeaderEncoding) == EncodingNone {
+			return msg, nil
+		}
+		result := &nats.Msg{
+			Subject: msg.Subject,
+			Header:  cloneHeader(msg.Header),
+			Data:    msg.Data,
+		}
+		return result, Decode(result)
+	}
+
+	index, err := strconv.Atoi(msg.Header.Get(HeaderChunkIndex))
+	if err != nil {
+		return nil, fmt.Errorf("bad chunk index: %w", err)
+	}
+	count, err := strconv.Atoi(msg.Header.Get(HeaderChunkCount))
+	if err != nil {
+		return nil, fmt.Errorf("bad chunk count: %w", err)
+	}
+	if count <= 0 || index < 0 || index >= count {
+		return nil, fmt.Errorf("bad chunk %d of %d", index, count)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	a.expire(now)
+
+	p, ok := a.partials[id]
+	if !ok {
+		p = &partial{chunks: make([][]byte, count), first: now}
+		a.partials[id] = p
+	}
+	if len(p.chunks) != count {
+		// The message was published again with a different content, the chunks of the earlier attempt can not be completed.
+		p = &partial{chunks: make([][]byte, count), first: now}
+		a.partials[id] = p
+	}
+	if p.chunks[index] != nil {
+		return nil, nil
+	}
+	p.chunks[index] = msg.Data
+	p.received++
+	p.size += len(msg.Data)
+	if p.received < count {
+		return nil, nil
+	}
+	delete(a.partials, id)
+
+	data := make([]byte, 0, p.size)
+	for _, chunk := range p.chunks {
+		data = append(data, chunk...)
+	}
+	header := cloneHeader(msg.Header)
+	header.Del(HeaderChunkId)
+	header.Del(HeaderChunkIndex)
+	header.Del(HeaderChunkCount)
+	if msgId := header.Get(nats.MsgIdHdr); msgId != "" {
+		header.Set(nats.MsgIdHdr, strings.TrimSuffix(msgId, "."+strconv.Itoa(index)))
+	}
+
+	result := &nats.Msg{
+		Subject: msg.Subject,
+		Header:  header,
+		Data:    data,
+	}
+	return result, Decode(result)
+}
+
+// Pending returns the number of incomplete messages.
+func (a *Assembler) Pending() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.partials)
+}
+
+func (a *Assembler) expire(now time.Time) {
+	if a.maxAge <= 0 {
+		return
+	}
+	for id, p := range a.partials {
+		if now.Sub(p.first) > a.maxAge {
+			delete(a.partials, id)
+		}
+	}
+}
+
+func cloneHeader(header nats.Header) nats.Header {
+	clone := make(nats.Header, len(header))
+	for k, v := range header {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
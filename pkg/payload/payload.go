@@ -0,0 +1,150 @@
+// Package payload implements compression and chunking of published messages
+// and reassembly of the chunks on the consumer side.
+//
+// A compressed message has the Content-Encoding header set to the compression algorithm.
+// A message that does not fit into the NATS max payload is split into chunks that share
+// the Chunk-Id header and carry Chunk-Index (starting from 0) and Chunk-Count headers.
+// The Chunk-Id of a message with a Nats-Msg-Id is derived from it, so that the chunks of a message
+// published again, e.g. by the outbox, complete the chunks that JetStream kept from an earlier attempt.
+// Chunks keep the subject and the rest of the headers of the original message.
+// The signature header always covers the original uncompressed payload.
+package payload
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
+)
+
+const (
+	HeaderEncoding   = "Content-Encoding"
+	HeaderChunkId    = "Chunk-Id"
+	HeaderChunkIndex = "Chunk-Index"
+	HeaderChunkCount = "Chunk-Count"
+
+	EncodingNone = ""
+	EncodingZstd = "zstd"
+	EncodingGzip = "gzip"
+)
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// Compress compresses data with the encoding.
+func Compress(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case EncodingNone:
+		return data, nil
+	case EncodingZstd:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	case EncodingGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+// Decompress decompresses data compressed with the encoding.
+func Decompress(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case EncodingNone:
+		return data, nil
+	case EncodingZstd:
+		return zstdDecoder.DecodeAll(data, nil)
+	case EncodingGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+// Encode compresses the message data in place and sets the encoding header.
+func Encode(encoding string, msg *nats.Msg) error {
+	if encoding == EncodingNone {
+		return nil
+	}
+	data, err := Compress(encoding, msg.Data)
+	if err != nil {
+		return err
+	}
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	msg.Header.Set(HeaderEncoding, encoding)
+	msg.Data = data
+	return nil
+}
+
+// Decode decompresses the message data in place and removes the encoding header.
+func Decode(msg *nats.Msg) error {
+	encoding := msg.Header.Get(HeaderEncoding)
+	if encoding == EncodingNone {
+		return nil
+	}
+	data, err := Decompress(encoding, msg.Data)
+	if err != nil {
+		return err
+	}
+	msg.Header.Del(HeaderEncoding)
+	msg.Data = data
+	return nil
+}
+
+// Split splits the message into chunks of at most chunkSize bytes of data.
+// The message itself is returned if it fits. A Nats-Msg-Id header gets the chunk index appended,
+// so that JetStream does not drop the chunks as duplicates of each other.
+func Split(msg *nats.Msg, chunkSize int) []*nats.Msg {
+	if chunkSize <= 0 || len(msg.Data) <= chunkSize {
+		return []*nats.Msg{msg}
+	}
+
+	id := nuid.Next()
+	if msgId := msg.Header.Get(nats.MsgIdHdr); msgId != "" {
+		sum := sha256.Sum256([]byte(msgId))
+		id = hex.EncodeToString(sum[:16])
+	}
+	count := (len(msg.Data) + chunkSize - 1) / chunkSize
+	chunks := make([]*nats.Msg, 0, count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*chunkSize, len(msg.Data))
+		header := make(nats.Header, len(msg.Header)+3)
+		for k, v := range msg.Header {
+			header[k] = v
+		}
+		header.Set(HeaderChunkId, id)
+		header.Set(HeaderChunkIndex, strconv.Itoa(i))
+		header.Set(HeaderChunkCount, strconv.Itoa(count))
+		if msgId := msg.Header.Get(nats.MsgIdHdr); msgId != "" {
+			header.Set(nats.MsgIdHdr, fmt.Sprintf("%s.%d", msgId, i))
+		}
+		chunks = append(chunks, &nats.Msg{
+			Subject: msg.Subject,
+			Header:  header,
+			Data:    msg.Data[i*chunkSize : end],
+		})
+	}
+	return chunks
+}
@@ -0,0 +1,157 @@
+package payload
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestCompress(t *testing.T) {
+	data := bytes.Repeat([]byte("osmosis block "), 1000)
+	for _, encoding := range []string{EncodingNone, EncodingZstd, EncodingGzip} {
+		compressed, err := Compress(encoding, data)
+		if err != nil {
+			t.Fatalf("Compress(%q) failed: %v", encoding, err)
+		}
+		if encoding != EncodingNone && len(compressed) >= len(data) {
+			t.Errorf("Compress(%q) did not compress: %d >= %d", encoding, len(compressed), len(data))
+		}
+		got, err := Decompress(encoding, compressed)
+		if err != nil {
+			t.Fatalf("Decompress(%q) failed: %v", encoding, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("Decompress(%q) returned different data", encoding)
+		}
+	}
+	if _, err := Compress("br", data); err == nil {
+		t.Errorf("Compress must fail for unknown encodings")
+	}
+}
+
+func TestSplitAndAssemble(t *testing.T) {
+	data := make([]byte, 10000)
+	rand.New(rand.NewSource(1)).Read(data)
+	msg := &nats.Msg{
+		Subject: "a.b.block",
+		Header:  nats.Header{"signature": {"sig"}, nats.MsgIdHdr: {"block.1"}},
+		Data:    data,
+	}
+	if err := Encode(EncodingZstd, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := Split(msg, 1000)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want more", len(chunks))
+	}
+	ids := make(map[string]struct{})
+	for _, c := range chunks {
+		if len(c.Data) > 1000 {
+			t.Errorf("chunk is %d bytes", len(c.Data))
+		}
+		ids[c.Header.Get(nats.MsgIdHdr)] = struct{}{}
+	}
+	if len(ids) != len(chunks) {
+		t.Errorf("chunks must have distinct message ids")
+	}
+
+	a := NewAssembler(time.Minute)
+	// Deliver in reverse order with a duplicate.
+	for i := len(chunks) - 1; i > 0; i-- {
+		got, err := a.Add(chunks[i])
+		if err != nil || got != nil {
+			t.Fatalf("Add(%d) = %v, %v, want pending", i, got, err)
+		}
+	}
+	a.Add(chunks[1])
+	got, err := a.Add(chunks[0])
+	if err != nil || got == nil {
+		t.Fatalf("Add(0) = %v, %v, want complete", got, err)
+	}
+	if !bytes.Equal(got.Data, data) {
+		t.Errorf("reassembled data differs")
+	}
+	if got.Subject != msg.Subject || got.Header.Get("signature") != "sig" || got.Header.Get(nats.MsgIdHdr) != "block.1" {
+		t.Errorf("unexpected headers %v", got.Header)
+	}
+	if got.Header.Get(HeaderChunkId) != "" || got.Header.Get(HeaderEncoding) != "" {
+		t.Errorf("chunk and encoding headers must be removed: %v", got.Header)
+	}
+	if a.Pending() != 0 {
+		t.Errorf("Pending = %d, want 0", a.Pending())
+	}
+}
+
+func TestAssembler_Plain(t *testing.T) {
+	msg := &nats.Msg{Subject: "a", Data: []byte("data")}
+	if chunks := Split(msg, 1000); len(chunks) != 1 || chunks[0] != msg {
+		t.Fatalf("small messages must not be split")
+	}
+	got, err := NewAssembler(0).Add(msg)
+	if err != nil || got != msg {
+		t.Errorf("Add = %v, %v, want the message", got, err)
+	}
+}
+
+func TestAssembler_Expire(t *testing.T) {
+	msg := &nats.Msg{Subject: "a", Data: make([]byte, 100)}
+	chunks := Split(msg, 10)
+	a := NewAssembler(time.Millisecond)
+	a.Add(chunks[0])
+	time.Sleep(time.Millisecond * 5)
+	a.Add(Split(msg, 10)[0])
+	if a.Pending() != 1 {
+		t.Errorf("Pending = %d, want 1", a.Pending())
+	}
+}
+
+func TestSplit_Republished(t *testing.T) {
+	data := bytes.Repeat([]byte("chunk"), 100)
+	first := Split(&nats.Msg{Subject: "a", Header: nats.Header{nats.MsgIdHdr: {"block.1"}}, Data: data}, 100)
+	again := Split(&nats.Msg{Subject: "a", Header: nats.Header{nats.MsgIdHdr: {"block.1"}}, Data: data}, 100)
+	if first[0].Header.Get(HeaderChunkId) != again[0].Header.Get(HeaderChunkId) {
+		t.Fatalf("chunk ids of a message with the same id differ")
+	}
+	other := Split(&nats.Msg{Subject: "a", Header: nats.Header{nats.MsgIdHdr: {"block.2"}}, Data: data}, 100)
+	if first[0].Header.Get(HeaderChunkId) == other[0].Header.Get(HeaderChunkId) {
+		t.Errorf("chunk ids of messages with different ids are equal")
+	}
+	plain := &nats.Msg{Subject: "a", Data: data}
+	if Split(plain, 100)[0].Header.Get(HeaderChunkId) == Split(plain, 100)[0].Header.Get(HeaderChunkId) {
+		t.Errorf("chunk ids of messages without an id must be unique")
+	}
+
+	// JetStream kept the first chunk of the failed attempt and dropped it from the second one.
+	a := NewAssembler(time.Minute)
+	a.Add(first[0])
+	var got *nats.Msg
+	for _, chunk := range again[1:] {
+		var err error
+		if got, err = a.Add(chunk); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if got == nil || !bytes.Equal(got.Data, data) {
+		t.Errorf("chunks of both attempts must be reassembled, got %v", got)
+	}
+}
+
+func TestAssembler_Encoded(t *testing.T) {
+	msg := &nats.Msg{Subject: "a", Header: nats.Header{"signature": {"sig"}}, Data: []byte("data")}
+	if err := Encode(EncodingGzip, msg); err != nil {
+		t.Fatal(err)
+	}
+	compressed := msg.Data
+
+	got, err := NewAssembler(0).Add(msg)
+	if err != nil || got == nil || string(got.Data) != "data" || got.Header.Get(HeaderEncoding) != "" {
+		t.Fatalf("Add = %v, %v, want the decompressed message", got, err)
+	}
+	if !bytes.Equal(msg.Data, compressed) || msg.Header.Get(HeaderEncoding) != EncodingGzip {
+		t.Errorf("Add must not modify the received message")
+	}
+}
@@ -0,0 +1,224 @@
+// Package recordlog implements a FIFO of records kept in a file.
+package recordlog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// ErrFull is returned when a record does not fit into the size limit of the log.
+var ErrFull = errors.New("record log is full")
+
+const compactSuffix = ".compact"
+
+// Log is a FIFO of records appended to a file, each prefixed with its length as a 4-byte big-endian integer.
+// Queued records may take up to maxBytes. The file is truncated every time the log is drained, and once half
+// of maxBytes has been consumed the queued records are moved to a new file, so the file never grows beyond
+// 1.5 times maxBytes even if the log never drains.
+//
+// With an offset file the read position is stored next to the log, so that the records survive restarts.
+// A Log is not safe for concurrent use.
+type Log struct {
+	file       *os.File
+	path       string
+	offsetPath string
+	maxBytes   int64
+	readOff    int64
+	writeOff   int64
+	count      int
+}
+
+// Open opens or creates the log at path. Zero maxBytes disables the size limit.
+// If offsetPath is not empty, the read position is loaded from it, and it is saved there by SaveOffset, Reset,
+// Close and compaction. An incomplete record at the end of the log is discarded.
+func Open(path, offsetPath string, maxBytes int64) (*Log, error) {
+	l := &Log{
+		path:       path,
+		offsetPath: offsetPath,
+		maxBytes:   maxBytes,
+	}
+	if offsetPath != "" {
+		if buf, err := os.ReadFile(offsetPath); err == nil {
+			l.readOff, _ = strconv.ParseInt(string(buf), 10, 64)
+		}
+	}
+	// A compacted log is complete once the read position is reset, see compact.
+	if _, err := os.Stat(l.compactPath()); err == nil {
+		if l.readOff == 0 {
+			err = os.Rename(l.compactPath(), path)
+		} else {
+			err = os.Remove(l.compactPath())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed recovering compaction: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l.file = file
+	if err := l.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) compactPath() string {
+	return l.path + compactSuffix
+}
+
+// load counts the records from the read position.
+func (l *Log) load() error {
+	info, err := l.file.Stat()
+	if err != nil {
+		return err
+	}
+	if l.readOff > info.Size() {
+		l.readOff = 0
+	}
+
+	l.writeOff = l.readOff
+	for {
+		n, err := l.recordSize(l.writeOff)
+		if err != nil || l.writeOff+n > info.Size() {
+			break
+		}
+		l.writeOff += n
+		l.count++
+	}
+	return l.file.Truncate(l.writeOff)
+}
+
+func (l *Log) recordSize(off int64) (int64, error) {
+	var header [4]byte
+	if _, err := l.file.ReadAt(header[:], off); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint32(header[:])) + 4, nil
+}
+
+// Len returns the number of queued records.
+func (l *Log) Len() int {
+	return l.count
+}
+
+// Size returns the size of the queued records including their headers.
+func (l *Log) Size() int64 {
+	return l.writeOff - l.readOff
+}
+
+// Push appends a record. ErrFull is returned if the record does not fit into the size limit.
+func (l *Log) Push(record []byte) error {
+	n := int64(len(record)) + 4
+	if l.maxBytes > 0 && l.Size()+n > l.maxBytes {
+		return ErrFull
+	}
+
+	buf := make([]byte, n)
+	binary.BigEndian.PutUint32(buf, uint32(len(record)))
+	copy(buf[4:], record)
+	if _, err := l.file.WriteAt(buf, l.writeOff); err != nil {
+		return err
+	}
+	l.writeOff += n
+	l.count++
+	return nil
+}
+
+// Peek returns the oldest record without removing it. io.EOF is returned if the log is empty.
+func (l *Log) Peek() ([]byte, error) {
+	if l.count == 0 {
+		return nil, io.EOF
+	}
+	n, err := l.recordSize(l.readOff)
+	if err != nil {
+		return nil, err
+	}
+	record := make([]byte, n-4)
+	if _, err := l.file.ReadAt(record, l.readOff+4); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Drop removes the oldest record. The log is reset when it is drained and compacted once half of maxBytes
+// has been consumed. If that fails, the record is removed anyway and the error is returned.
+func (l *Log) Drop() error {
+	if l.count == 0 {
+		return io.EOF
+	}
+	n, err := l.recordSize(l.readOff)
+	if err != nil {
+		return err
+	}
+	l.readOff += n
+	l.count--
+
+	if l.count == 0 {
+		return l.Reset()
+	}
+	if l.maxBytes > 0 && l.readOff >= l.maxBytes/2 {
+		return l.compact()
+	}
+	return nil
+}
+
+// compact moves the queued records to a new file and drops the consumed ones.
+// The new file replaces the log only after the read position is reset, so that a crash in between
+// reads the old log from the start instead of reading the new one at a stale position.
+func (l *Log) compact() error {
+	path := l.compactPath()
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	size := l.Size()
+	if _, err := io.Copy(file, io.NewSectionReader(l.file, l.readOff, size)); err != nil {
+		return errors.Join(err, file.Close(), os.Remove(path))
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(err, file.Close(), os.Remove(path))
+	}
+
+	readOff := l.readOff
+	l.readOff = 0
+	if err := l.SaveOffset(); err != nil {
+		l.readOff = readOff
+		return errors.Join(err, file.Close(), os.Remove(path))
+	}
+	if err := os.Rename(path, l.path); err != nil {
+		// The old log is still in place, so it is read from the start after a restart.
+		l.readOff = readOff
+		return errors.Join(err, file.Close(), l.SaveOffset())
+	}
+	l.file.Close()
+	l.file = file
+	l.writeOff = size
+	return nil
+}
+
+// Reset drops all the records.
+func (l *Log) Reset() error {
+	l.readOff, l.writeOff, l.count = 0, 0, 0
+	return errors.Join(l.file.Truncate(0), l.SaveOffset())
+}
+
+// SaveOffset stores the read position if the log has an offset file.
+func (l *Log) SaveOffset() error {
+	if l.offsetPath == "" {
+		return nil
+	}
+	return os.WriteFile(l.offsetPath, []byte(strconv.FormatInt(l.readOff, 10)), 0o644)
+}
+
+// Close stores the read position and closes the log.
+func (l *Log) Close() error {
+	return errors.Join(l.SaveOffset(), l.file.Close())
+}
@@ -0,0 +1,145 @@
+package recordlog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "log"), "", 20)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+
+	for _, r := range []string{"first", "second"} {
+		if err := l.Push([]byte(r)); err != nil {
+			t.Fatalf("Push(%s) failed: %v", r, err)
+		}
+	}
+	if err := l.Push([]byte("third")); !errors.Is(err, ErrFull) {
+		t.Fatalf("Push over the limit error = %v, want ErrFull", err)
+	}
+
+	for _, want := range []string{"first", "second"} {
+		got, err := l.Peek()
+		if err != nil || string(got) != want {
+			t.Fatalf("Peek() = %q, %v, want %q", got, err, want)
+		}
+		if err := l.Drop(); err != nil {
+			t.Fatalf("Drop() failed: %v", err)
+		}
+	}
+	if _, err := l.Peek(); err != io.EOF {
+		t.Errorf("Peek() of an empty log error = %v, want io.EOF", err)
+	}
+	if l.Size() != 0 {
+		t.Errorf("Size() = %d after drain, want 0", l.Size())
+	}
+	if err := l.Push([]byte("third")); err != nil {
+		t.Errorf("Push after drain failed: %v", err)
+	}
+}
+
+func TestLog_compaction(t *testing.T) {
+	const maxBytes = 100
+	dir := t.TempDir()
+	path, offsetPath := filepath.Join(dir, "log"), filepath.Join(dir, "offset")
+	l, err := Open(path, offsetPath, maxBytes)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	// The log never drains, but consumed records must not count against the limit.
+	next, want := 0, 0
+	for ; next < 3; next++ {
+		if err := l.Push([]byte(fmt.Sprintf("record-%03d", next))); err != nil {
+			t.Fatalf("Push(%d) failed: %v", next, err)
+		}
+	}
+	for ; next < 1000; next++ {
+		if err := l.Push([]byte(fmt.Sprintf("record-%03d", next))); err != nil {
+			t.Fatalf("Push(%d) with %d bytes queued failed: %v", next, l.Size(), err)
+		}
+		got, err := l.Peek()
+		if err != nil || string(got) != fmt.Sprintf("record-%03d", want) {
+			t.Fatalf("Peek() = %q, %v, want record-%03d", got, err, want)
+		}
+		if err := l.Drop(); err != nil {
+			t.Fatalf("Drop() failed: %v", err)
+		}
+		want++
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if info.Size() > maxBytes*3/2 {
+			t.Fatalf("log is %d bytes, want at most %d", info.Size(), maxBytes*3/2)
+		}
+	}
+	if l.Len() != 3 || l.Size() != 3*14 {
+		t.Errorf("Len() = %d, Size() = %d, want 3 records of 42 bytes", l.Len(), l.Size())
+	}
+	l.Close()
+
+	// The read position survives a restart.
+	l, err = Open(path, offsetPath, maxBytes)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+	if got, err := l.Peek(); l.Len() != 3 || err != nil || string(got) != fmt.Sprintf("record-%03d", want) {
+		t.Errorf("reopened log = %d records, Peek() = %q, %v, want 3 from record-%03d", l.Len(), got, err, want)
+	}
+}
+
+func TestOpen_interruptedCompaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  string
+		want    string
+		wantLen int
+	}{
+		// The read position was reset, so the compacted log is complete.
+		{"offset reset", "0", "new", 1},
+		// The crash happened before the read position was reset, so the old log is still valid.
+		{"offset kept", "4", "b", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path, offsetPath := filepath.Join(dir, "log"), filepath.Join(dir, "offset")
+			l, err := Open(path, "", 0)
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			l.Push(nil)
+			l.Push([]byte("b"))
+			l.Close()
+			compacted, err := Open(path+compactSuffix, "", 0)
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			compacted.Push([]byte("new"))
+			compacted.Close()
+			os.WriteFile(offsetPath, []byte(tt.offset), 0o644)
+
+			l, err = Open(path, offsetPath, 0)
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			defer l.Close()
+			if got, err := l.Peek(); l.Len() != tt.wantLen || err != nil || string(got) != tt.want {
+				t.Errorf("log = %d records, Peek() = %q, %v, want %d from %q", l.Len(), got, err, tt.wantLen, tt.want)
+			}
+			if _, err := os.Stat(path + compactSuffix); !os.IsNotExist(err) {
+				t.Errorf("compacted log is left behind: %v", err)
+			}
+		})
+	}
+}
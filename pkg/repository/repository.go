@@ -27,12 +27,24 @@ type Repository interface {
 	// PoolsRange will return a list of available pools from minimum to maximum heights
 	PoolsRange(minHeight, maxHeight, poolId uint64) ([]Pool, error)
 
+	// PoolsPage will return up to limit pools from minimum to maximum heights ordered by height after skipping offset,
+	// and the number of pools between the heights
+	PoolsPage(minHeight, maxHeight, poolId uint64, offset, limit int) ([]Pool, int, error)
+
 	// TokenPriceRange will return stored token prices between and including min/max timestamps
 	TokenPricesRange(min, max time.Time, denom string) ([]TokenPrice, error)
 
+	// TokenPricesPage will return up to limit token prices between and including min/max timestamps ordered by time
+	// after skipping offset, and the number of token prices between the timestamps
+	TokenPricesPage(min, max time.Time, denom string, offset, limit int) ([]TokenPrice, int, error)
+
+	// Checkpoint will return the last height published on the subject
+	Checkpoint(subject string) (uint64, bool)
+
 	SaveIBCDenom(IBCTypes.DenomTrace) error
 	SaveTokenPrice(TokenPrice) error
 	SavePool(Pool) error
+	SaveCheckpoint(subject string, height uint64) error
 
 	// PruneTokenPrices will remove all token prices prior timestamp.
 	PruneTokenPrices(timestamp time.Time) (int, error)
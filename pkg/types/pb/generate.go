@@ -0,0 +1,4 @@
+// Package pb contains protobuf types of the messages published when protobuf encoding is selected.
+package pb
+
+//go:generate protoc -I ../../../proto --go_opt=module=github.com/synternet/osmosis-publisher/pkg/types/pb --go_out=./ ../../../proto/osmosis/publisher/types.proto
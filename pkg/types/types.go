@@ -13,6 +13,8 @@ type Transaction struct {
 	Tx       any    `json:"tx"`
 	TxResult any    `json:"tx_result"`
 	Metadata any    `json:"metadata"`
+	// RawBytes is the transaction as included in the block. It is used by protobuf encoding.
+	RawBytes []byte `json:"-"`
 }
 
 func (*Transaction) ProtoReflect() protoreflect.Message { return nil }